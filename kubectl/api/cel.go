@@ -0,0 +1,471 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ValidationsLabel is the OpenAPI extension key holding CEL validation rules.
+const ValidationsLabel string = "x-kubernetes-validations"
+
+// celCostLimit bounds the runtime cost of a single rule evaluation, in line
+// with the per-expression limit enforced by the API server.
+const celCostLimit uint64 = 1000000
+
+// ValidationRule is a single entry of x-kubernetes-validations.
+type ValidationRule struct {
+	Rule              string `json:"rule"`
+	Message           string `json:"message,omitempty"`
+	MessageExpression string `json:"messageExpression,omitempty"`
+	FieldPath         string `json:"fieldPath,omitempty"`
+	OptionalOldSelf   bool   `json:"optionalOldSelf,omitempty"`
+}
+
+type celProgram struct {
+	prg        cel.Program
+	output     *cel.Type
+	transition bool
+	// selfFields holds the paths of the fields of self the rule selects,
+	// e.g. [spec replicas] for self.spec.replicas.
+	selfFields [][]string
+}
+
+var (
+	celEnvOnce   sync.Once
+	celEnvShared *cel.Env
+	celEnvErr    error
+	celPrograms  sync.Map // string -> *celProgram
+)
+
+// celEnv returns the environment rules are compiled in. It declares self and
+// oldSelf as dynamically typed and enables the string, list and set
+// extensions commonly used in CRD rules. Rules calling Kubernetes-specific
+// library functions fail to compile and are left to the API server.
+func celEnv() (*cel.Env, error) {
+	celEnvOnce.Do(func() {
+		celEnvShared, celEnvErr = cel.NewEnv(
+			cel.Variable("self", cel.DynType),
+			cel.Variable("oldSelf", cel.DynType),
+			cel.OptionalTypes(),
+			cel.CrossTypeNumericComparisons(true),
+			ext.Strings(),
+			ext.Lists(),
+			ext.Sets(),
+		)
+	})
+	return celEnvShared, celEnvErr
+}
+
+// compileCEL compiles expr once and caches the resulting program.
+func compileCEL(expr string) (*celProgram, error) {
+	if p, ok := celPrograms.Load(expr); ok {
+		return p.(*celProgram), nil //nolint:forcetypeassert
+	}
+	env, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	checked, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	prg, err := env.Program(checked, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, err
+	}
+	p := &celProgram{prg: prg, output: checked.OutputType()}
+	for _, ref := range checked.NativeRep().ReferenceMap() {
+		if ref.Name == "oldSelf" {
+			p.transition = true
+			break
+		}
+	}
+	ast.PreOrderVisit(checked.NativeRep().Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if fp, ok := selfFieldPath(e); ok && len(fp) > 0 {
+			p.selfFields = append(p.selfFields, fp)
+		}
+	}))
+	celPrograms.Store(expr, p)
+	return p, nil
+}
+
+// ValidateCELRules evaluates the x-kubernetes-validations rules of sch and its
+// nested schemas against obj. Transition rules, which refer to oldSelf, are
+// evaluated against the corresponding value in old, and skipped where there is
+// none unless the rule sets optionalOldSelf.
+//
+// A rule is only reported when it evaluates to false. Rules that fail to
+// compile or evaluate, e.g. because they depend on server-side defaults or
+// Kubernetes-specific functions, and rules over values that contain nulls or
+// not-yet-known values, are left to the API server. Rules that refer to
+// fields missing from obj may hold once the API server has defaulted them,
+// so their failures are reported as warnings.
+func ValidateCELRules(
+	obj map[string]any,
+	old map[string]any,
+	sch *openapi3.Schema,
+	defs map[string]*openapi3.SchemaRef,
+) []ValidationError {
+	v := &celValidator{validator: validator{defs: defs}}
+	var oldVal any
+	if old != nil {
+		oldVal = old
+	}
+	v.walk(obj, oldVal, sch, tftypes.NewAttributePath(), 50)
+	return v.errs
+}
+
+type celValidator struct {
+	validator
+}
+
+func (v *celValidator) walk(
+	val any,
+	old any,
+	sch *openapi3.Schema,
+	ap *tftypes.AttributePath,
+	depth int,
+) {
+	if val == nil || sch == nil || depth == 0 {
+		return
+	}
+
+	if rules := schemaValidationRules(sch); len(rules) > 0 && !containsNil(val) {
+		self := v.celNative(val, sch, depth)
+		var oldSelf any
+		if old != nil && !containsNil(old) {
+			oldSelf = v.celNative(old, sch, depth)
+		}
+		for _, r := range rules {
+			v.evaluate(r, self, oldSelf, ap)
+		}
+	}
+
+	switch tv := val.(type) {
+	case map[string]any:
+		oldMap, _ := old.(map[string]any)
+		var additional *openapi3.Schema
+		if sch.AdditionalProperties.Schema != nil {
+			additional, _ = resolveSchemaRef(sch.AdditionalProperties.Schema, v.defs)
+		}
+		for k, e := range tv {
+			cs := additional
+			if pref, ok := sch.Properties[k]; ok {
+				cs, _ = resolveSchemaRef(pref, v.defs)
+			}
+			if cs == nil {
+				continue
+			}
+			var oe any
+			if oldMap != nil {
+				oe = oldMap[k]
+			}
+			v.walk(e, oe, cs, ap.WithAttributeName(k), depth-1)
+		}
+	case []any:
+		if sch.Items == nil {
+			return
+		}
+		is, err := resolveSchemaRef(sch.Items, v.defs)
+		if err != nil {
+			return
+		}
+		oldList, _ := old.([]any)
+		for i, e := range tv {
+			v.walk(e, correlateListItem(e, oldList, sch), is, ap.WithElementKeyInt(i), depth-1)
+		}
+	}
+}
+
+func (v *celValidator) evaluate(r ValidationRule, self, oldSelf any, ap *tftypes.AttributePath) {
+	p, err := compileCEL(r.Rule)
+	if err != nil {
+		return
+	}
+	vars := map[string]any{"self": self}
+	if p.transition {
+		switch {
+		case r.OptionalOldSelf && oldSelf == nil:
+			vars["oldSelf"] = types.OptionalNone
+		case r.OptionalOldSelf:
+			vars["oldSelf"] = types.OptionalOf(types.DefaultTypeAdapter.NativeToValue(oldSelf))
+		case oldSelf == nil:
+			return
+		default:
+			vars["oldSelf"] = oldSelf
+		}
+	}
+	out, _, err := p.prg.Eval(vars)
+	if err != nil {
+		return
+	}
+	if ok, isBool := out.Value().(bool); !isBool || ok {
+		return
+	}
+
+	msg := r.Message
+	if r.MessageExpression != "" {
+		if mp, err := compileCEL(r.MessageExpression); err == nil {
+			if mo, _, err := mp.prg.Eval(vars); err == nil {
+				if s, ok := mo.Value().(string); ok && s != "" {
+					msg = s
+				}
+			}
+		}
+	}
+	if msg == "" {
+		msg = fmt.Sprintf("failed rule: %s", r.Rule)
+	}
+	fp := appendFieldPath(ap, r.FieldPath)
+	if missing := missingSelfField(self, p.selfFields); missing != "" {
+		v.errs = append(v.errs, ValidationError{
+			Path:    fp,
+			Summary: "Validation rule may fail",
+			Detail: fmt.Sprintf("%s: %s (the rule refers to %s, which is not set in the "+
+				"manifest and may be defaulted by the API server)",
+				FieldPathString(fp), msg, missing),
+			Warning: true,
+		})
+		return
+	}
+	v.addError(fp, "Validation rule failed", "%s", msg)
+}
+
+// selfFieldPath returns the path of the field of self that e selects, either
+// as self.a.b or with optional selection as self.?a.?b.
+func selfFieldPath(e ast.Expr) ([]string, bool) {
+	switch e.Kind() {
+	case ast.IdentKind:
+		return nil, e.AsIdent() == "self"
+	case ast.SelectKind:
+		s := e.AsSelect()
+		fp, ok := selfFieldPath(s.Operand())
+		return append(fp, s.FieldName()), ok
+	case ast.CallKind:
+		c := e.AsCall()
+		args := c.Args()
+		if c.FunctionName() != operators.OptSelect || len(args) != 2 ||
+			args[1].Kind() != ast.LiteralKind {
+			return nil, false
+		}
+		name, isString := args[1].AsLiteral().Value().(string)
+		fp, ok := selfFieldPath(args[0])
+		return append(fp, name), ok && isString
+	}
+	return nil, false
+}
+
+// missingSelfField returns the first of fields that is absent from self,
+// rendered as self.a.b, or "" if all are set. Fields below lists and scalars
+// are not checked.
+func missingSelfField(self any, fields [][]string) string {
+	for _, fp := range fields {
+		cur := self
+		for i, k := range fp {
+			m, ok := cur.(map[string]any)
+			if !ok {
+				break
+			}
+			if cur, ok = m[k]; !ok {
+				return "self." + strings.Join(fp[:i+1], ".")
+			}
+		}
+	}
+	return ""
+}
+
+// celNative converts val for use as a CEL variable: whole numbers in fields
+// declared as integers become int64, so that rules doing integer arithmetic
+// behave as they do on the API server.
+func (v *celValidator) celNative(val any, sch *openapi3.Schema, depth int) any {
+	if sch == nil || depth == 0 {
+		return val
+	}
+	switch tv := val.(type) {
+	case float64:
+		if (sch.Type.Is(openapi3.TypeInteger) || isIntOrString(sch)) && tv == math.Trunc(tv) {
+			return int64(tv)
+		}
+	case map[string]any:
+		var additional *openapi3.Schema
+		if sch.AdditionalProperties.Schema != nil {
+			additional, _ = resolveSchemaRef(sch.AdditionalProperties.Schema, v.defs)
+		}
+		out := make(map[string]any, len(tv))
+		for k, e := range tv {
+			cs := additional
+			if pref, ok := sch.Properties[k]; ok {
+				cs, _ = resolveSchemaRef(pref, v.defs)
+			}
+			out[k] = v.celNative(e, cs, depth-1)
+		}
+		return out
+	case []any:
+		var is *openapi3.Schema
+		if sch.Items != nil {
+			is, _ = resolveSchemaRef(sch.Items, v.defs)
+		}
+		out := make([]any, len(tv))
+		for i, e := range tv {
+			out[i] = v.celNative(e, is, depth-1)
+		}
+		return out
+	}
+	return val
+}
+
+// schemaValidationRules decodes the x-kubernetes-validations extension of sch.
+func schemaValidationRules(sch *openapi3.Schema) []ValidationRule {
+	ex, ok := sch.Extensions[ValidationsLabel]
+	if !ok {
+		return nil
+	}
+	raw, ok := ex.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(ex); err != nil {
+			return nil
+		}
+	}
+	var rules []ValidationRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil
+	}
+	return rules
+}
+
+// ImmutableHint is the type hint recorded for fields whose
+// x-kubernetes-validations forbid changing them once set, with the transition
+// rule self == oldSelf on the field or self.field == oldSelf.field on its
+// parent.
+const ImmutableHint string = "immutable"
+
+const celSelection = `(self|oldSelf)((?:\.[A-Za-z_][A-Za-z0-9_]*)*)`
+
+var immutabilityRule = regexp.MustCompile(
+	`^\s*` + celSelection + `\s*==\s*` + celSelection + `\s*$`)
+
+// immutableFieldPaths returns the paths, relative to sch, of the fields the
+// x-kubernetes-validations of sch declare immutable. The empty path stands
+// for sch itself.
+func immutableFieldPaths(sch *openapi3.Schema) [][]string {
+	var paths [][]string
+	for _, r := range schemaValidationRules(sch) {
+		m := immutabilityRule.FindStringSubmatch(r.Rule)
+		if r.OptionalOldSelf || m == nil || m[1] == m[3] || m[2] != m[4] {
+			continue
+		}
+		paths = append(paths, strings.Split(m[2], ".")[1:])
+	}
+	return paths
+}
+
+// correlateListItem finds the prior version of item in old. As on the API
+// server, only items of map-type lists are correlated, by their key fields.
+func correlateListItem(item any, old []any, sch *openapi3.Schema) any {
+	if len(old) == 0 || extensionString(sch.Extensions["x-kubernetes-list-type"]) != "map" {
+		return nil
+	}
+	var keys []string
+	if raw, err := json.Marshal(sch.Extensions["x-kubernetes-list-map-keys"]); err == nil {
+		_ = json.Unmarshal(raw, &keys)
+	}
+	im, ok := item.(map[string]any)
+	if !ok || len(keys) == 0 {
+		return nil
+	}
+	for _, o := range old {
+		om, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+		match := true
+		for _, k := range keys {
+			if fmt.Sprintf("%v", im[k]) != fmt.Sprintf("%v", om[k]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return om
+		}
+	}
+	return nil
+}
+
+func extensionString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.RawMessage:
+		var s string
+		if err := json.Unmarshal(val, &s); err == nil {
+			return s
+		}
+	}
+	return ""
+}
+
+func containsNil(v any) bool {
+	switch tv := v.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		for _, e := range tv {
+			if containsNil(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range tv {
+			if containsNil(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var fieldPathStep = regexp.MustCompile(`^(?:\.([A-Za-z0-9_$-]+)|\['([^']*)'\]|\[(\d+)\])`)
+
+// appendFieldPath extends ap with the JSON path of a rule's fieldPath, e.g.
+// .spec.ports[0] or .labels['app']. Unparsable paths leave ap unchanged.
+func appendFieldPath(ap *tftypes.AttributePath, fp string) *tftypes.AttributePath {
+	out := ap
+	for rest := fp; rest != ""; {
+		m := fieldPathStep.FindStringSubmatch(rest)
+		if m == nil {
+			return ap
+		}
+		switch {
+		case m[1] != "":
+			out = out.WithAttributeName(m[1])
+		case m[3] != "":
+			i, err := strconv.Atoi(m[3])
+			if err != nil {
+				return ap
+			}
+			out = out.WithElementKeyInt(i)
+		default:
+			out = out.WithAttributeName(m[2])
+		}
+		rest = rest[len(m[0]):]
+	}
+	return out
+}
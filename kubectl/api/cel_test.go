@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const celTestSpec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "io.example.Gadget": {
+        "type": "object",
+        "x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Gadget"}],
+        "properties": {
+          "spec": {
+            "type": "object",
+            "x-kubernetes-validations": [
+              {"rule": "self.min <= self.max", "message": "min must not exceed max"},
+              {"rule": "self.min % 2 == 0", "messageExpression": "'min ' + string(self.min) + ' is odd'"},
+              {"rule": "!has(self.name) || self.name.startsWith('g-')", "fieldPath": ".name"}
+            ],
+            "properties": {
+              "min": {"type": "integer"},
+              "max": {"type": "integer"},
+              "name": {"type": "string"},
+              "class": {
+                "type": "string",
+                "x-kubernetes-validations": [
+                  {"rule": "self == oldSelf", "message": "class is immutable"}
+                ]
+              },
+              "tier": {
+                "type": "string",
+                "x-kubernetes-validations": [
+                  {"rule": "!oldSelf.hasValue() || oldSelf.value() == self", "optionalOldSelf": true}
+                ]
+              },
+              "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+              "policy": {
+                "type": "object",
+                "x-kubernetes-validations": [{
+                  "rule": "self.?mode.orValue('') == 'auto' || self.?schedule.orValue('') != ''",
+                  "message": "a schedule is required unless mode is auto"
+                }],
+                "properties": {
+                  "mode": {"type": "string", "default": "auto"},
+                  "schedule": {"type": "string"}
+                }
+              },
+              "custom": {
+                "type": "string",
+                "x-kubernetes-validations": [{"rule": "isSorted(self)"}]
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestValidateCELRules(t *testing.T) {
+	f, err := NewFoundryFromSpecV3([]byte(celTestSpec))
+	if err != nil {
+		t.Fatalf("foundry: %s", err)
+	}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}
+	sch, defs, err := f.GetSchemaByGVK(gvk)
+	if err != nil {
+		t.Fatalf("schema: %s", err)
+	}
+
+	samples := map[string]struct {
+		spec     map[string]any
+		oldSpec  map[string]any
+		expected map[string]string
+		warnings map[string]string
+	}{
+		"valid": {
+			spec: map[string]any{"min": float64(2), "max": float64(4), "name": "g-1"},
+		},
+		"message": {
+			spec:     map[string]any{"min": float64(6), "max": float64(4)},
+			expected: map[string]string{"spec": "spec: min must not exceed max"},
+		},
+		"messageExpression": {
+			spec:     map[string]any{"min": float64(1), "max": float64(4)},
+			expected: map[string]string{"spec": "spec: min 1 is odd"},
+		},
+		"fieldPath": {
+			spec: map[string]any{"min": float64(2), "max": float64(4), "name": "x"},
+			expected: map[string]string{
+				"spec.name": "spec.name: failed rule: !has(self.name) || self.name.startsWith('g-')",
+			},
+		},
+		"unknown values are skipped": {
+			spec: map[string]any{"min": nil, "max": float64(4)},
+		},
+		"evaluation errors are skipped": {
+			spec: map[string]any{"max": float64(4)},
+		},
+		"unsupported functions are skipped": {
+			spec: map[string]any{"min": float64(2), "max": float64(4), "custom": "x"},
+		},
+		"transition rule without prior state": {
+			spec: map[string]any{"min": float64(2), "max": float64(4), "class": "a"},
+		},
+		"transition rule unchanged": {
+			spec:    map[string]any{"min": float64(2), "max": float64(4), "class": "a"},
+			oldSpec: map[string]any{"min": float64(2), "max": float64(4), "class": "a"},
+		},
+		"transition rule changed": {
+			spec:     map[string]any{"min": float64(2), "max": float64(4), "class": "b"},
+			oldSpec:  map[string]any{"min": float64(2), "max": float64(4), "class": "a"},
+			expected: map[string]string{"spec.class": "spec.class: class is immutable"},
+		},
+		"optional oldSelf": {
+			spec:    map[string]any{"min": float64(2), "max": float64(4), "tier": "b"},
+			oldSpec: map[string]any{"min": float64(2), "max": float64(4), "tier": "a"},
+			expected: map[string]string{
+				"spec.tier": "spec.tier: failed rule: !oldSelf.hasValue() || oldSelf.value() == self",
+			},
+		},
+		"rule over fields set in the manifest": {
+			spec: map[string]any{
+				"min": float64(2), "max": float64(4),
+				"policy": map[string]any{"mode": "manual", "schedule": ""},
+			},
+			expected: map[string]string{
+				"spec.policy": "spec.policy: a schedule is required unless mode is auto",
+			},
+		},
+		"rule over fields missing from the manifest": {
+			spec: map[string]any{
+				"min": float64(2), "max": float64(4),
+				"policy": map[string]any{},
+			},
+			warnings: map[string]string{
+				"spec.policy": "spec.policy: a schedule is required unless mode is auto " +
+					"(the rule refers to self.mode, which is not set in the manifest " +
+					"and may be defaulted by the API server)",
+			},
+		},
+		"rule over fields missing from the manifest that holds": {
+			spec: map[string]any{
+				"min": float64(2), "max": float64(4),
+				"policy": map[string]any{"schedule": "@daily"},
+			},
+		},
+	}
+
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			obj := map[string]any{"spec": s.spec}
+			var old map[string]any
+			if s.oldSpec != nil {
+				old = map[string]any{"spec": s.oldSpec}
+			}
+			errs := ValidateCELRules(obj, old, sch, defs)
+			if len(errs) != len(s.expected)+len(s.warnings) {
+				t.Fatalf("expected %d errors and %d warnings, got %v",
+					len(s.expected), len(s.warnings), errs)
+			}
+			for _, e := range errs {
+				p := FieldPathString(e.Path)
+				expected := s.expected
+				if e.Warning {
+					expected = s.warnings
+				}
+				if expected[p] != e.Detail {
+					t.Fatalf("unexpected error at %s (warning: %t): %q", p, e.Warning, e.Detail)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendFieldPath(t *testing.T) {
+	ap := appendFieldPath(tftypes.NewAttributePath(), ".spec.ports[1]['app.kubernetes.io/name']")
+	if got := FieldPathString(ap); got != "spec.ports[1].app.kubernetes.io/name" {
+		t.Fatalf("unexpected path %s", got)
+	}
+	if got := appendFieldPath(tftypes.NewAttributePath(), "spec"); len(got.Steps()) != 0 {
+		t.Fatalf("expected invalid path to be ignored, got %s", got)
+	}
+}
+
+func TestImmutableFieldPaths(t *testing.T) {
+	samples := map[string]struct {
+		rules    []any
+		expected [][]string
+	}{
+		"self": {
+			rules:    []any{map[string]any{"rule": "self == oldSelf"}},
+			expected: [][]string{{}},
+		},
+		"reversed": {
+			rules:    []any{map[string]any{"rule": " oldSelf==self "}},
+			expected: [][]string{{}},
+		},
+		"child fields": {
+			rules: []any{
+				map[string]any{"rule": "self.storageClass == oldSelf.storageClass"},
+				map[string]any{"rule": "oldSelf.a.b == self.a.b"},
+			},
+			expected: [][]string{{"storageClass"}, {"a", "b"}},
+		},
+		"different fields": {
+			rules: []any{map[string]any{"rule": "self.a == oldSelf.b"}},
+		},
+		"not a transition rule": {
+			rules: []any{map[string]any{"rule": "self == self"}},
+		},
+		"optional old self": {
+			rules: []any{map[string]any{"rule": "self == oldSelf", "optionalOldSelf": true}},
+		},
+		"other rule": {
+			rules: []any{map[string]any{"rule": "self.min <= self.max"}},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			sch := &openapi3.Schema{Extensions: map[string]any{ValidationsLabel: s.rules}}
+			if got := immutableFieldPaths(sch); !reflect.DeepEqual(got, s.expected) {
+				t.Fatalf("expected %v, got %v", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestImmutableHints(t *testing.T) {
+	f, err := NewFoundryFromSpecV3([]byte(celTestSpec))
+	if err != nil {
+		t.Fatalf("foundry: %s", err)
+	}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}
+	_, hints, err := f.GetTypeByGVK(gvk)
+	if err != nil {
+		t.Fatalf("type: %s", err)
+	}
+	class := tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("class")
+	tier := tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("tier")
+	if hints[class.String()] != ImmutableHint {
+		t.Fatalf("expected spec.class to be immutable, got hints %v", hints)
+	}
+	if _, ok := hints[tier.String()]; ok {
+		t.Fatalf("expected no hint for spec.tier, got hints %v", hints)
+	}
+}
@@ -12,7 +12,6 @@
 	"k8s.io/apimachinery/pkg/api/meta"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	apiMachineryTypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -22,7 +21,6 @@
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -96,78 +94,60 @@ func (r *RestClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
 }
 
 // GetRestClientFromUnstructured creates a dynamic client for the given manifest.
-// Adapted from SDK v2 kubernetes/resource_kubectl_manifest.go.
+// The GroupVersionResource is resolved through the shared RESTMapper, which is
+// only reset (forcing a fresh discovery) when the kind is not found, e.g. when a
+// CRD has just been created by another resource.
 func GetRestClientFromUnstructured(
 	ctx context.Context,
 	manifest *yaml.Manifest,
-	clientset *kubernetes.Clientset,
-	restConfig *restclient.Config,
+	mapper meta.RESTMapper,
+	client dynamic.Interface,
 ) *RestClientResult {
 	doGetRestClient := func() *RestClientResult {
-		// Use the k8s Discovery service to find all valid APIs for this cluster
-		discoveryClient := clientset.Discovery()
-		_, resources, err := discoveryClient.ServerGroupsAndResources()
-
-		// There is a partial failure mode here where not all groups are returned
-		// We'll try to continue if it's a GroupDiscoveryFailedError
-		if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
-			return RestClientResultFromErr(err)
-		}
-
-		// Validate that the APIVersion provided in the YAML is valid for this cluster
-		apiResource, exists := CheckAPIResourceIsPresent(resources, *manifest.Raw)
-		if !exists {
+		gvk := manifest.Raw.GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
 			// API not found, try invalidating cache and retrying
-			// This handles the case when a CRD is being created by another resource
-			_, resources, err = discoveryClient.ServerGroupsAndResources()
-
-			if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
-				return RestClientResultFromErr(err)
-			}
-
-			// Check for resource again
-			apiResource, exists = CheckAPIResourceIsPresent(resources, *manifest.Raw)
-			if !exists {
-				return RestClientResultFromInvalidTypeErr(
-					fmt.Errorf(
-						"resource [%s/%s] isn't valid for cluster, check the APIVersion and Kind fields are valid",
-						manifest.Raw.GroupVersionKind().GroupVersion().String(),
-						manifest.GetKind(),
-					),
-				)
+			if rm, ok := mapper.(meta.ResettableRESTMapper); ok {
+				rm.Reset()
+				mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 			}
 		}
-
-		resourceStruct := k8sschema.GroupVersionResource{
-			Group:    apiResource.Group,
-			Version:  apiResource.Version,
-			Resource: apiResource.Name,
+		if meta.IsNoMatchError(err) {
+			log.Printf(
+				"[ERROR] Could not find a valid ApiResource for manifest %s/%s/%s",
+				gvk.Group,
+				gvk.Version,
+				gvk.Kind,
+			)
+			return RestClientResultFromInvalidTypeErr(
+				fmt.Errorf(
+					"resource [%s/%s] isn't valid for cluster, check the APIVersion and Kind fields are valid",
+					gvk.GroupVersion().String(),
+					manifest.GetKind(),
+				),
+			)
 		}
-
-		// For core services (ServiceAccount, Service etc) the group is incorrectly parsed.
-		// "v1" should be empty group and "v1" for version
-		if resourceStruct.Group == "v1" && resourceStruct.Version == "" {
-			resourceStruct.Group = ""
-			resourceStruct.Version = "v1"
+		if err != nil {
+			return RestClientResultFromErr(err)
 		}
 
-		// Get dynamic client based on the found resource struct
-		client := dynamic.NewForConfigOrDie(restConfig).Resource(resourceStruct)
+		rcl := client.Resource(mapping.Resource)
 
 		// If the resource is namespaced and doesn't have a namespace defined, set it to default
-		if apiResource.Namespaced {
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
 			if !manifest.HasNamespace() {
 				manifest.SetNamespace("default")
 			}
-			return RestClientResultSuccess(client.Namespace(manifest.GetNamespace()))
+			return RestClientResultSuccess(rcl.Namespace(manifest.GetNamespace()))
 		}
 
-		return RestClientResultSuccess(client)
+		return RestClientResultSuccess(rcl)
 	}
 
 	// Run with timeout
 	discoveryWithTimeout := func() <-chan *RestClientResult {
-		ch := make(chan *RestClientResult)
+		ch := make(chan *RestClientResult, 1)
 		go func() {
 			ch <- doGetRestClient()
 		}()
@@ -180,6 +160,8 @@ func GetRestClientFromUnstructured(
 	select {
 	case res := <-discoveryWithTimeout():
 		return res
+	case <-ctx.Done():
+		return RestClientResultFromErr(ctx.Err())
 	case <-timeout.C:
 		log.Printf("[ERROR] %v timed out fetching resources from discovery client", manifest)
 		return RestClientResultFromErr(
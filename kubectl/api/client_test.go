@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// resettableMapper counts Reset calls and only knows about its kinds once reset.
+type resettableMapper struct {
+	meta.RESTMapper
+	pending func() meta.RESTMapper
+	resets  int
+}
+
+func (m *resettableMapper) Reset() {
+	m.resets++
+	if m.pending != nil {
+		m.RESTMapper = m.pending()
+	}
+}
+
+func newTestManifest(apiVersion, kind, namespace string) *yaml.Manifest {
+	uo := &meta_v1_unstruct.Unstructured{}
+	uo.SetAPIVersion(apiVersion)
+	uo.SetKind(kind)
+	uo.SetName("test")
+	if namespace != "" {
+		uo.SetNamespace(namespace)
+	}
+	return yaml.NewFromUnstructured(uo)
+}
+
+func TestGetRestClientFromUnstructured(t *testing.T) {
+	cmGVK := k8sschema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	crGVK := k8sschema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+	known := meta.NewDefaultRESTMapper(nil)
+	known.Add(cmGVK, meta.RESTScopeNamespace)
+
+	withCRD := meta.NewDefaultRESTMapper(nil)
+	withCRD.Add(cmGVK, meta.RESTScopeNamespace)
+	withCRD.Add(crGVK, meta.RESTScopeRoot)
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	ctx := context.Background()
+
+	t.Run("namespaced kind defaults namespace", func(t *testing.T) {
+		m := &resettableMapper{RESTMapper: known}
+		manifest := newTestManifest("v1", "ConfigMap", "")
+		res := GetRestClientFromUnstructured(ctx, manifest, m, client)
+		if res.Error != nil {
+			t.Fatalf("unexpected error: %s", res.Error)
+		}
+		if manifest.GetNamespace() != "default" {
+			t.Fatalf("expected default namespace, got %q", manifest.GetNamespace())
+		}
+		if m.resets != 0 {
+			t.Fatalf("expected no mapper reset on hit, got %d", m.resets)
+		}
+	})
+
+	t.Run("miss resets mapper and retries", func(t *testing.T) {
+		m := &resettableMapper{
+			RESTMapper: known,
+			pending:    func() meta.RESTMapper { return withCRD },
+		}
+		manifest := newTestManifest("example.com/v1", "Widget", "")
+		res := GetRestClientFromUnstructured(ctx, manifest, m, client)
+		if res.Error != nil {
+			t.Fatalf("unexpected error: %s", res.Error)
+		}
+		if m.resets != 1 {
+			t.Fatalf("expected one mapper reset, got %d", m.resets)
+		}
+		if manifest.HasNamespace() {
+			t.Fatalf("cluster-scoped kind should not get a namespace")
+		}
+	})
+
+	t.Run("unknown kind is an error", func(t *testing.T) {
+		m := &resettableMapper{RESTMapper: known}
+		manifest := newTestManifest("example.com/v1", "Gadget", "")
+		res := GetRestClientFromUnstructured(ctx, manifest, m, client)
+		if res.Error == nil {
+			t.Fatal("expected error for unknown kind")
+		}
+	})
+}
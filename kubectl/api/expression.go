@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/hashicorp/go-hclog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Expression is a CEL expression over a live object, bound to self, and the
+// object as it was before the apply, bound to oldSelf.
+type Expression struct {
+	Expr string
+	// OldSelf is the object before the apply, nil when it did not exist,
+	// which oldSelf sees as null.
+	OldSelf *unstructured.Unstructured
+
+	prg *celProgram
+}
+
+// CompileExpression compiles expr, which must evaluate to a bool.
+func CompileExpression(expr string) (*Expression, error) {
+	p, err := compileCEL(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	switch p.output.Kind() {
+	case types.BoolKind, types.DynKind, types.AnyKind:
+	default:
+		return nil, fmt.Errorf("expression %q must evaluate to a bool, not %s",
+			expr, p.output)
+	}
+	return &Expression{Expr: expr, prg: p}, nil
+}
+
+// UsesOldSelf reports whether the expression refers to oldSelf.
+func (e *Expression) UsesOldSelf() bool {
+	return e != nil && e.prg.transition
+}
+
+// Eval reports whether the expression holds for obj. It returns an error
+// when the expression cannot be evaluated, e.g. because it selects a field
+// obj does not have yet, or does not evaluate to a bool.
+func (e *Expression) Eval(obj *unstructured.Unstructured) (bool, error) {
+	vars := map[string]any{"self": obj.Object, "oldSelf": types.NullValue}
+	if e.OldSelf != nil {
+		vars["oldSelf"] = e.OldSelf.Object
+	}
+	out, _, err := e.prg.prg.Eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate expression %q: %w", e.Expr, err)
+	}
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q evaluated to %v, not a bool", e.Expr, out)
+	}
+	return b, nil
+}
+
+// ExpressionWaiter will wait for a CEL expression over the resource to
+// hold.
+type ExpressionWaiter struct {
+	Resource     dynamic.ResourceInterface
+	ResourceName string
+	Expression   *Expression
+	Logger       hclog.Logger
+
+	// lastErr is the error of the last evaluation, nil when it succeeded.
+	lastErr error
+}
+
+// Wait blocks until the expression holds.
+func (w *ExpressionWaiter) Wait(ctx context.Context) error {
+	w.Logger.Info("[Wait] Waiting for expression...\n")
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err != nil {
+		return w.TimeoutError(err)
+	}
+
+	w.Logger.Info("[Wait] Expression holds.\n")
+	return nil
+}
+
+// Reason describes what the waiter waits on.
+func (w *ExpressionWaiter) Reason() string {
+	return "expression"
+}
+
+// Done reports whether the expression holds for res. An expression that
+// cannot be evaluated yet, e.g. because it selects a status field the
+// controller has not set, does not hold.
+func (w *ExpressionWaiter) Done(res *unstructured.Unstructured) (bool, error) {
+	done, err := w.Expression.Eval(res)
+	w.lastErr = err
+	if err != nil {
+		w.Logger.Debug(fmt.Sprintf("[Wait] %v", err))
+		return false, nil
+	}
+	return done, nil
+}
+
+// TimeoutError adds the error of the last evaluation of the expression to
+// err when the wait timed out, since it usually tells why the expression
+// never held.
+func (w *ExpressionWaiter) TimeoutError(err error) error {
+	var we WaiterError
+	if w.lastErr == nil || !errors.As(err, &we) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", err, w.lastErr)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func expressionTestObject(generation int64, conditions ...any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "test", "generation": generation},
+		"status":     map[string]any{"conditions": conditions},
+	}}
+}
+
+func TestExpression(t *testing.T) {
+	ready := map[string]any{"type": "Ready", "status": "True", "reason": "Available"}
+	degraded := map[string]any{"type": "Synced", "status": "True", "reason": "Degraded"}
+	compound := "self.status.conditions.exists(c, c.type == 'Ready' && c.status == 'True') && " +
+		"!self.status.conditions.exists(c, c.reason == 'Degraded')"
+
+	samples := map[string]struct {
+		expr     string
+		obj      *unstructured.Unstructured
+		old      *unstructured.Unstructured
+		expected bool
+		err      bool
+	}{
+		"compound holds": {
+			expr:     compound,
+			obj:      expressionTestObject(1, ready),
+			expected: true,
+		},
+		"compound fails": {
+			expr: compound,
+			obj:  expressionTestObject(1, ready, degraded),
+		},
+		"oldSelf null on create": {
+			expr:     "oldSelf == null",
+			obj:      expressionTestObject(1),
+			expected: true,
+		},
+		"oldSelf before apply": {
+			expr:     "self.metadata.generation > oldSelf.metadata.generation",
+			obj:      expressionTestObject(2),
+			old:      expressionTestObject(1),
+			expected: true,
+		},
+		"missing field": {
+			expr: "self.spec.replicas == 3",
+			obj:  expressionTestObject(1),
+			err:  true,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			e, err := CompileExpression(s.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.OldSelf = s.old
+			holds, err := e.Eval(s.obj)
+			if (err != nil) != s.err {
+				t.Fatalf("expected error to be %t, got %v", s.err, err)
+			}
+			if holds != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, holds)
+			}
+		})
+	}
+}
+
+func TestCompileExpression(t *testing.T) {
+	if _, err := CompileExpression("self.metadata.name"); err != nil {
+		t.Fatalf("expected a dynamically typed expression to compile, got %v", err)
+	}
+	if _, err := CompileExpression("1 + 1"); err == nil {
+		t.Fatal("expected an error for an expression that is not a bool")
+	}
+	if _, err := CompileExpression("self.status."); err == nil {
+		t.Fatal("expected an error for an invalid expression")
+	}
+	if e, _ := CompileExpression("self == oldSelf"); !e.UsesOldSelf() {
+		t.Fatal("expected the expression to use oldSelf")
+	}
+}
+
+func TestExpressionWaiterDone(t *testing.T) {
+	e, err := CompileExpression("self.status.phase == 'Running'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &ExpressionWaiter{Expression: e, Logger: hclog.NewNullLogger()}
+	if done, err := w.Done(expressionTestObject(1)); done || err != nil {
+		t.Fatalf("expected a missing field not to end the wait, got %t, %v", done, err)
+	}
+	err = w.TimeoutError(WaiterError{Reason: "expression"})
+	var we WaiterError
+	if !errors.As(err, &we) || !strings.Contains(err.Error(), "failed to evaluate expression") {
+		t.Fatalf("expected the timeout to include the evaluation error, got %v", err)
+	}
+	obj := expressionTestObject(1)
+	obj.Object["status"] = map[string]any{"phase": "Running"}
+	if done, err := w.Done(obj); !done || err != nil {
+		t.Fatalf("expected the wait to be done, got %t, %v", done, err)
+	}
+	if err := w.TimeoutError(WaiterError{Reason: "expression"}); err.Error() !=
+		"timed out waiting on expression" {
+		t.Fatalf("expected no evaluation error after a successful evaluation, got %v", err)
+	}
+}
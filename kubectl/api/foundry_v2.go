@@ -12,6 +12,7 @@
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -55,6 +56,11 @@ func NewFoundryFromSpecV2(spec []byte) (Foundry, error) {
 // Foundry is a mechanism to construct tftypes out of OpenAPI specifications.
 type Foundry interface {
 	GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[string]string, error)
+	// GetSchemaByGVK returns the OpenAPI schema of gvk together with the
+	// definitions its references resolve against.
+	GetSchemaByGVK(
+		gvk schema.GroupVersionKind,
+	) (*openapi3.Schema, map[string]*openapi3.SchemaRef, error)
 }
 
 type foapiv2 struct {
@@ -63,6 +69,7 @@ type foapiv2 struct {
 	gkvIndex       sync.Map
 	recursionDepth uint64 // a last resort circuit-breaker for run-away recursion - hitting this will make for a bad day
 	gate           sync.Mutex
+	v3Definitions  map[string]*openapi3.SchemaRef
 }
 
 // GetTypeByGVK looks up a type by its GVK in the Definitions sections of
@@ -93,6 +100,37 @@ func (f *foapiv2) GetTypeByGVK(
 	return t, hints, err
 }
 
+// GetSchemaByGVK looks up the definition of gvk and returns it converted to
+// an OpenAPI v3 schema.
+func (f *foapiv2) GetSchemaByGVK(
+	gvk schema.GroupVersionKind,
+) (*openapi3.Schema, map[string]*openapi3.SchemaRef, error) {
+	f.gate.Lock()
+	defer f.gate.Unlock()
+
+	id := "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+	if gvk != ObjectMetaGVK {
+		v, ok := f.gkvIndex.Load(gvk)
+		if !ok {
+			return nil, nil, fmt.Errorf("%v resource not found in OpenAPI index", gvk)
+		}
+		id = v.(string)
+	}
+	swd, ok := f.swagger.Definitions[id]
+	if !ok || swd == nil {
+		return nil, nil, errors.New("invalid type identifier")
+	}
+
+	if f.v3Definitions == nil {
+		f.v3Definitions = openapi2conv.ToV3Schemas(f.swagger.Definitions)
+	}
+	sch, err := resolveSchemaRef(openapi2conv.ToV3SchemaRef(swd), f.v3Definitions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve schema: %s", err)
+	}
+	return sch, f.v3Definitions, nil
+}
+
 func (f *foapiv2) getTypeByID(
 	id string,
 	h map[string]string,
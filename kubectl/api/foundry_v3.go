@@ -4,6 +4,8 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -12,13 +14,31 @@
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// NewFoundryFromSpecV3 creates a new tftypes.Type foundry from an OpenAPI v3 document.
+// The document can either be a CRD schema wrapped with SchemaToSpec, or a
+// per-group-version document served by the API server at /openapi/v3/<path>.
+//
+// References are deliberately left unresolved and looked up by name on use, as
+// for v2 specs. Resolving them eagerly would produce cyclic schemas for
+// recursive types such as JSONSchemaProps.
 func NewFoundryFromSpecV3(spec []byte) (Foundry, error) {
-	loader := openapi3.NewLoader()
-	oapi3, err := loader.LoadFromData(spec)
-	if err != nil {
-		return nil, err
+	if len(spec) < 6 { // unlikely to be valid json
+		return nil, errors.New("empty spec")
+	}
+
+	var oapi3 openapi3.T
+	if err := oapi3.UnmarshalJSON(spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %s", err)
+	}
+	if oapi3.Components == nil || len(oapi3.Components.Schemas) == 0 {
+		return nil, errors.New("spec has no type information")
 	}
-	return &foapiv3{doc: oapi3}, nil
+
+	f := &foapiv3{doc: &oapi3}
+	if err := f.buildGvkIndex(); err != nil {
+		return nil, fmt.Errorf("failed to build GVK index when creating new foundry: %s", err)
+	}
+	return f, nil
 }
 
 func SchemaToSpec(key string, crschema map[string]any) map[string]any {
@@ -45,22 +65,86 @@ type foapiv3 struct {
 	doc       *openapi3.T
 	gate      sync.Mutex
 	typeCache sync.Map
+	gkvIndex  sync.Map
 }
 
-func (f *foapiv3) GetTypeByGVK(_ schema.GroupVersionKind) (tftypes.Type, map[string]string, error) {
+// GetTypeByGVK looks up a type by its GVK among the component schemas. Schemas
+// wrapped with SchemaToSpec carry no GVK and are stored under the empty key,
+// which is used when the GVK is not found in the index.
+func (f *foapiv3) GetTypeByGVK(
+	gvk schema.GroupVersionKind,
+) (tftypes.Type, map[string]string, error) {
 	f.gate.Lock()
 	defer f.gate.Unlock()
 
 	hints := make(map[string]string)
 	ap := tftypes.AttributePath{}
 
-	sref := f.doc.Components.Schemas[""]
-
-	sch, err := resolveSchemaRef(sref, f.doc.Components.Schemas)
+	sch, err := f.schemaByGVK(gvk)
 	if err != nil {
-		return nil, hints, fmt.Errorf("failed to resolve schema: %s", err)
+		return nil, hints, err
 	}
 
 	tftype, err := getTypeFromSchema(sch, 50, &(f.typeCache), f.doc.Components.Schemas, ap, hints)
 	return tftype, hints, err
 }
+
+// GetSchemaByGVK returns the component schema of gvk, looked up as in GetTypeByGVK.
+func (f *foapiv3) GetSchemaByGVK(
+	gvk schema.GroupVersionKind,
+) (*openapi3.Schema, map[string]*openapi3.SchemaRef, error) {
+	f.gate.Lock()
+	defer f.gate.Unlock()
+
+	sch, err := f.schemaByGVK(gvk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sch, f.doc.Components.Schemas, nil
+}
+
+func (f *foapiv3) schemaByGVK(gvk schema.GroupVersionKind) (*openapi3.Schema, error) {
+	id := ""
+	if gvk == ObjectMetaGVK {
+		id = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+	} else if v, ok := f.gkvIndex.Load(gvk); ok {
+		id = v.(string) //nolint:forcetypeassert
+	}
+
+	sref, ok := f.doc.Components.Schemas[id]
+	if !ok || sref == nil {
+		return nil, fmt.Errorf("%v resource not found in OpenAPI index", gvk)
+	}
+
+	sch, err := resolveSchemaRef(sref, f.doc.Components.Schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schema: %s", err)
+	}
+	return sch, nil
+}
+
+// buildGvkIndex builds the reverse lookup index that associates each GVK
+// to its corresponding key in the components.schemas map.
+func (f *foapiv3) buildGvkIndex() error {
+	for id, sref := range f.doc.Components.Schemas {
+		if sref == nil || sref.Value == nil {
+			continue
+		}
+		ex, ok := sref.Value.Extensions["x-kubernetes-group-version-kind"]
+		if !ok {
+			continue
+		}
+		exBytes, err := json.Marshal(ex)
+		if err != nil {
+			return fmt.Errorf("failed to marshal GVK from OpenAPI schema extension: %v", err)
+		}
+		gvk := []schema.GroupVersionKind{}
+		if err := json.Unmarshal(exBytes, &gvk); err != nil {
+			return fmt.Errorf("failed to unmarshall GVK from OpenAPI schema extension: %v", err)
+		}
+		for i := range gvk {
+			f.gkvIndex.Store(gvk[i], id)
+		}
+	}
+	return nil
+}
@@ -6,6 +6,9 @@
 import (
 	"encoding/json"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestNewFoundryFromSpecV3(t *testing.T) {
@@ -71,3 +74,66 @@ func TestNewFoundryFromSpecV3(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestFoundryV3GroupVersionDocument(t *testing.T) {
+	doc := map[string]any{
+		"openapi": "3.0.0",
+		"info":    map[string]any{"title": "Kubernetes", "version": "v1.31.0"},
+		"paths":   map[string]any{},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"io.k8s.api.core.v1.ConfigMap": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"apiVersion": map[string]any{"type": "string"},
+						"kind":       map[string]any{"type": "string"},
+						"metadata": map[string]any{
+							"allOf": []any{
+								map[string]any{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+							},
+							"default": map[string]any{},
+						},
+						"immutable": map[string]any{"type": "boolean"},
+					},
+					"x-kubernetes-group-version-kind": []any{
+						map[string]any{"group": "", "version": "v1", "kind": "ConfigMap"},
+					},
+				},
+				"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	j, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Error: %+v", err)
+	}
+
+	f, err := NewFoundryFromSpecV3(j)
+	if err != nil {
+		t.Fatalf("Error: %+v", err)
+	}
+
+	typ, _, err := f.GetTypeByGVK(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	if err != nil {
+		t.Fatalf("Error: %+v", err)
+	}
+	expected := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"metadata":   tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String}},
+		"immutable":  tftypes.Bool,
+	}}
+	if !typ.Equal(expected) {
+		t.Fatalf("unexpected type: %s", typ)
+	}
+
+	_, _, err = f.GetTypeByGVK(schema.GroupVersionKind{Version: "v1", Kind: "Secret"})
+	if err == nil {
+		t.Fatal("expected error for GVK missing from document")
+	}
+}
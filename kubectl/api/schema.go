@@ -24,21 +24,18 @@ func resolveSchemaRef(
 	defs map[string]*openapi3.SchemaRef,
 ) (*openapi3.Schema, error) {
 	if ref.Value != nil {
+		// OpenAPI v3 documents served by Kubernetes wrap references to other
+		// types as {"allOf": [{"$ref": ...}]} so that a description or default
+		// can sit alongside them. Treat such a wrapper as the referenced type.
+		if len(ref.Value.AllOf) == 1 && ref.Value.Type == nil && len(ref.Value.Properties) == 0 {
+			return resolveSchemaRef(ref.Value.AllOf[0], defs)
+		}
 		return ref.Value, nil
 	}
 
 	rp := strings.Split(ref.Ref, "/")
 	sid := rp[len(rp)-1]
 
-	nref, ok := defs[sid]
-
-	if !ok {
-		return nil, errors.New("schema not found")
-	}
-	if nref == nil {
-		return nil, errors.New("nil schema reference")
-	}
-
 	// These are exceptional situations that require non-standard types.
 	switch sid {
 	case "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.JSONSchemaProps":
@@ -49,9 +46,52 @@ func resolveSchemaRef(
 		return &t, nil
 	}
 
+	nref, ok := defs[sid]
+
+	if !ok {
+		return nil, errors.New("schema not found")
+	}
+	if nref == nil {
+		return nil, errors.New("nil schema reference")
+	}
+
 	return resolveSchemaRef(nref, defs)
 }
 
+// HasSchemaDefault reports whether sch declares a default value for the field
+// at path, where "*" stands for any item of a list.
+func HasSchemaDefault(
+	sch *openapi3.Schema,
+	defs map[string]*openapi3.SchemaRef,
+	path []string,
+) bool {
+	for i, name := range path {
+		if sch == nil {
+			return false
+		}
+		ref := sch.Properties[name]
+		if name == "*" && sch.Items != nil {
+			ref = sch.Items
+		}
+		if ref == nil {
+			ref = sch.AdditionalProperties.Schema
+		}
+		if ref == nil {
+			return false
+		}
+		next, err := resolveSchemaRef(ref, defs)
+		if err != nil {
+			return false
+		}
+		if i == len(path)-1 {
+			// the default of a referenced type sits next to the reference
+			return next.Default != nil || (ref.Value != nil && ref.Value.Default != nil)
+		}
+		sch = next
+	}
+	return false
+}
+
 func getTypeFromSchema(
 	elem *openapi3.Schema,
 	stackdepth uint64,
@@ -82,13 +122,27 @@ func getTypeFromSchema(
 		}
 	}
 
+	// Fields declared immutable by transition rules are hinted as such, unless
+	// they carry a type hint already.
+	for _, steps := range immutableFieldPaths(elem) {
+		fp := ap
+		for _, name := range steps {
+			fp = *fp.WithAttributeName(name)
+		}
+		if _, ok := th[fp.String()]; !ok {
+			th[fp.String()] = ImmutableHint
+		}
+	}
+
 	// check if type is in cache
-	// HACK: this is temporarily disabled to diagnose a cache corruption issue.
-	// if herr == nil {
-	// 	if t, ok := typeCache.Load(h); ok {
-	// 		return t.(tftypes.Type), nil
-	// 	}
-	// }
+	if herr == nil {
+		if v, ok := typeCache.Load(h); ok {
+			if e, ok := v.(typeCacheEntry); ok {
+				e.applyHints(ap, th)
+				return e.t, nil
+			}
+		}
+	}
 	//nolint:staticcheck
 	switch {
 	case elem.Type.Is(openapi3.TypeString):
@@ -133,7 +187,7 @@ func getTypeFromSchema(
 				t = tftypes.List{ElementType: et}
 			}
 			if herr == nil {
-				typeCache.Store(h, t)
+				typeCache.Store(h, newTypeCacheEntry(t, ap, th))
 			}
 			return t, nil
 		case elem.AdditionalProperties.Has != nil && elem.Items == nil: // "overridden" array - translates to a tftypes.Tuple
@@ -170,7 +224,7 @@ func getTypeFromSchema(
 			}
 			t = tftypes.Object{AttributeTypes: atts}
 			if herr == nil {
-				typeCache.Store(h, t)
+				typeCache.Store(h, newTypeCacheEntry(t, ap, th))
 			}
 			return t, nil
 
@@ -187,7 +241,7 @@ func getTypeFromSchema(
 			}
 			t = tftypes.Map{ElementType: pt}
 			if herr == nil {
-				typeCache.Store(h, t)
+				typeCache.Store(h, newTypeCacheEntry(t, ap, th))
 			}
 			return t, nil
 
@@ -195,7 +249,7 @@ func getTypeFromSchema(
 			// this is a strange case, encountered with io.k8s.apimachinery.pkg.apis.meta.v1.FieldsV1 and also io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1.CustomResourceSubresourceStatus
 			t = tftypes.DynamicPseudoType
 			if herr == nil {
-				typeCache.Store(h, t)
+				typeCache.Store(h, newTypeCacheEntry(t, ap, th))
 			}
 			return t, nil
 
@@ -205,6 +259,48 @@ func getTypeFromSchema(
 	return nil, fmt.Errorf("unknown type: %s", elem.Type)
 }
 
+// typeCacheEntry is a type built from a schema together with the type hints
+// that were recorded for its subtree. Hints are keyed by attribute path, so
+// they are stored relative to the path the type was first built at and
+// re-rooted at the path of every later cache hit. The same schema reached
+// through different attributes therefore yields the same type and correctly
+// placed hints.
+type typeCacheEntry struct {
+	t     tftypes.Type
+	hints map[string]string // relative path suffix -> hint
+}
+
+func newTypeCacheEntry(
+	t tftypes.Type,
+	ap tftypes.AttributePath,
+	th map[string]string,
+) typeCacheEntry {
+	prefix := ap.String()
+	hints := make(map[string]string)
+	for k, v := range th {
+		switch {
+		case k == prefix:
+			hints[""] = v
+		case prefix == "":
+			hints["."+k] = v
+		case strings.HasPrefix(k, prefix+"."):
+			hints[k[len(prefix):]] = v
+		}
+	}
+	return typeCacheEntry{t: t, hints: hints}
+}
+
+func (e typeCacheEntry) applyHints(ap tftypes.AttributePath, th map[string]string) {
+	prefix := ap.String()
+	for k, v := range e.hints {
+		key := prefix + k
+		if prefix == "" {
+			key = strings.TrimPrefix(k, ".")
+		}
+		th[key] = v
+	}
+}
+
 // extensionBool safely extracts a boolean from an OpenAPI extension value.
 // Extension values may be bool, json.RawMessage, or other types depending on
 // the kin-openapi version. Returns false for nil or non-boolean values.
@@ -5,8 +5,11 @@
 
 import (
 	"encoding/json"
+	"reflect"
+	"sync"
 	"testing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
@@ -125,3 +128,110 @@ func(t *testing.T) {
 			})
 	}
 }
+
+func TestGetTypeFromSchemaCacheHints(t *testing.T) {
+	shared := func() *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type: &openapi3.Types{openapi3.TypeObject},
+			Properties: openapi3.Schemas{
+				"port": &openapi3.SchemaRef{Value: &openapi3.Schema{
+					Type:   &openapi3.Types{openapi3.TypeString},
+					Format: "int-or-string",
+				}},
+				"extra": &openapi3.SchemaRef{Value: &openapi3.Schema{
+					Type:       &openapi3.Types{openapi3.TypeObject},
+					Extensions: map[string]any{PreserveUnknownFieldsLabel: true},
+				}},
+			},
+		}}
+	}
+	root := &openapi3.Schema{
+		Type: &openapi3.Types{openapi3.TypeObject},
+		Properties: openapi3.Schemas{
+			"a": shared(),
+			"b": shared(),
+		},
+	}
+
+	expected := map[string]string{
+		`AttributeName("a").AttributeName("port")`:  "io.k8s.apimachinery.pkg.util.intstr.IntOrString",
+		`AttributeName("a").AttributeName("extra")`: PreserveUnknownFieldsLabel,
+		`AttributeName("b").AttributeName("port")`:  "io.k8s.apimachinery.pkg.util.intstr.IntOrString",
+		`AttributeName("b").AttributeName("extra")`: PreserveUnknownFieldsLabel,
+	}
+
+	var typeCache sync.Map
+	var first tftypes.Type
+	// The second pass is served from the cache populated by the first one.
+	for i := range 2 {
+		hints := make(map[string]string)
+		typ, err := getTypeFromSchema(root, 50, &typeCache, nil, tftypes.AttributePath{}, hints)
+		if err != nil {
+			t.Fatalf("pass %d: %s", i, err)
+		}
+		if first == nil {
+			first = typ
+		} else if !typ.Equal(first) {
+			t.Fatalf("pass %d: type %s differs from %s", i, typ, first)
+		}
+		if !reflect.DeepEqual(hints, expected) {
+			t.Fatalf("pass %d: unexpected hints %v", i, hints)
+		}
+	}
+}
+
+func TestHasSchemaDefault(t *testing.T) {
+	str := func(def any) *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:    &openapi3.Types{openapi3.TypeString},
+			Default: def,
+		}}
+	}
+	defs := map[string]*openapi3.SchemaRef{
+		"io.k8s.api.core.v1.ServicePort": {Value: &openapi3.Schema{
+			Type: &openapi3.Types{openapi3.TypeObject},
+			Properties: openapi3.Schemas{
+				"protocol": str("TCP"),
+				"name":     str(nil),
+			},
+		}},
+	}
+	root := &openapi3.Schema{
+		Type: &openapi3.Types{openapi3.TypeObject},
+		Properties: openapi3.Schemas{
+			"spec": {Value: &openapi3.Schema{
+				Type: &openapi3.Types{openapi3.TypeObject},
+				Properties: openapi3.Schemas{
+					"sessionAffinity": {Value: &openapi3.Schema{
+						AllOf:   openapi3.SchemaRefs{str(nil)},
+						Default: "None",
+					}},
+					"ports": {Value: &openapi3.Schema{
+						Type: &openapi3.Types{openapi3.TypeArray},
+						Items: &openapi3.SchemaRef{
+							Ref: "#/components/schemas/io.k8s.api.core.v1.ServicePort",
+						},
+					}},
+				},
+			}},
+		},
+	}
+
+	samples := map[string]struct {
+		path     []string
+		expected bool
+	}{
+		"default next to a reference":  {[]string{"spec", "sessionAffinity"}, true},
+		"default of a list item field": {[]string{"spec", "ports", "*", "protocol"}, true},
+		"field without default":        {[]string{"spec", "ports", "*", "name"}, false},
+		"object without default":       {[]string{"spec", "ports"}, false},
+		"undeclared field":             {[]string{"spec", "clusterIP"}, false},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			if got := HasSchemaDefault(root, defs, s.path); got != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, got)
+			}
+		})
+	}
+}
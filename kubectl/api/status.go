@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Status is the readiness of an object, following the kstatus rules of
+// sigs.k8s.io/cli-utils.
+type Status string
+
+// Statuses computed by ComputeStatus.
+const (
+	InProgressStatus  Status = "InProgress"
+	CurrentStatus     Status = "Current"
+	FailedStatus      Status = "Failed"
+	TerminatingStatus Status = "Terminating"
+)
+
+// statusRule computes the status of objects of one kind.
+type statusRule func(obj *unstructured.Unstructured) (Status, string)
+
+// statusRules are the rules for built-in kinds whose readiness is not
+// expressed by Ready, Reconciling and Stalled conditions.
+var statusRules = map[schema.GroupKind]statusRule{
+	{Group: "apps", Kind: "Deployment"}:                               deploymentStatus,
+	{Group: "apps", Kind: "StatefulSet"}:                              statefulSetStatus,
+	{Group: "apps", Kind: "DaemonSet"}:                                daemonSetStatus,
+	{Group: "apps", Kind: "ReplicaSet"}:                               replicaSetStatus,
+	{Group: "batch", Kind: "Job"}:                                     jobStatus,
+	{Group: "", Kind: "Pod"}:                                          podStatus,
+	{Group: "", Kind: "PersistentVolumeClaim"}:                        pvcStatus,
+	{Group: "", Kind: "Service"}:                                      serviceStatus,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: crdStatus,
+}
+
+// ComputeStatus returns the status of obj and a message explaining it.
+//
+// An object being deleted is Terminating. An object whose latest generation
+// the controller has not observed yet, or that has a Reconciling condition,
+// is InProgress, and one with a Stalled condition is Failed. Built-in kinds
+// then follow their own rules, and any other kind is Current unless it has a
+// Ready condition that is not True.
+func ComputeStatus(obj *unstructured.Unstructured) (Status, string) {
+	if obj.GetDeletionTimestamp() != nil {
+		return TerminatingStatus, "Resource is being deleted"
+	}
+
+	observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && observed != obj.GetGeneration() {
+		return InProgressStatus, fmt.Sprintf(
+			"%s generation is %d, but latest observed generation is %d",
+			obj.GetKind(), obj.GetGeneration(), observed)
+	}
+
+	conditions := objectConditions(obj)
+	if c := findCondition(conditions, "Reconciling"); c != nil && c["status"] == "True" {
+		return InProgressStatus, conditionMessage(c)
+	}
+	if c := findCondition(conditions, "Stalled"); c != nil && c["status"] == "True" {
+		return FailedStatus, conditionMessage(c)
+	}
+
+	if rule, ok := statusRules[obj.GroupVersionKind().GroupKind()]; ok {
+		return rule(obj)
+	}
+
+	if c := findCondition(conditions, "Ready"); c != nil && c["status"] != "True" {
+		return InProgressStatus, conditionMessage(c)
+	}
+	return CurrentStatus, "Resource is current"
+}
+
+// findCondition returns the first condition of conditionType in conditions.
+func findCondition(conditions []any, conditionType string) map[string]any {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if ok && condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
+// conditionMessage describes condition by its type, reason and message.
+func conditionMessage(condition map[string]any) string {
+	msg := fmt.Sprintf("%v", condition["type"])
+	if reason, ok := condition["reason"].(string); ok && reason != "" {
+		msg += ": " + reason
+	}
+	if message, ok := condition["message"].(string); ok && message != "" {
+		msg += ": " + message
+	}
+	return msg
+}
+
+// statusInt returns the integer at fields of obj, or 0.
+func statusInt(obj *unstructured.Unstructured, fields ...string) int64 {
+	v, _, _ := unstructured.NestedInt64(obj.Object, fields...)
+	return v
+}
+
+// specReplicas returns the spec.replicas of obj, which defaults to 1.
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	v, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+	return v
+}
+
+func deploymentStatus(obj *unstructured.Unstructured) (Status, string) {
+	replicas := specReplicas(obj)
+	updated := statusInt(obj, "status", "updatedReplicas")
+	current := statusInt(obj, "status", "replicas")
+	ready := statusInt(obj, "status", "readyReplicas")
+	available := statusInt(obj, "status", "availableReplicas")
+
+	c := findCondition(objectConditions(obj), "Progressing")
+	if c != nil && c["reason"] == "ProgressDeadlineExceeded" {
+		return InProgressStatus, conditionMessage(c)
+	}
+	switch {
+	case updated < replicas:
+		return InProgressStatus, fmt.Sprintf("Updated: %d/%d", updated, replicas)
+	case current > updated:
+		return InProgressStatus, fmt.Sprintf("Pending termination: %d", current-updated)
+	case available < updated:
+		return InProgressStatus, fmt.Sprintf("Available: %d/%d", available, updated)
+	case ready < replicas:
+		return InProgressStatus, fmt.Sprintf("Ready: %d/%d", ready, replicas)
+	}
+	return CurrentStatus, fmt.Sprintf("Deployment is available. Replicas: %d", current)
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured) (Status, string) {
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return CurrentStatus, "StatefulSet is using the OnDelete update strategy"
+	}
+	replicas := specReplicas(obj)
+	partition, _, _ := unstructured.NestedInt64(obj.Object,
+		"spec", "updateStrategy", "rollingUpdate", "partition")
+	ready := statusInt(obj, "status", "readyReplicas")
+	updated := statusInt(obj, "status", "updatedReplicas")
+	current := statusInt(obj, "status", "currentReplicas")
+
+	switch {
+	case ready < replicas:
+		return InProgressStatus, fmt.Sprintf("Ready: %d/%d", ready, replicas)
+	case partition > 0:
+		if updated < replicas-partition {
+			return InProgressStatus, fmt.Sprintf("Updated: %d/%d", updated, replicas-partition)
+		}
+		return CurrentStatus, fmt.Sprintf("Partitioned rollout complete. Updated: %d", updated)
+	case current < replicas:
+		return InProgressStatus, fmt.Sprintf("Current: %d/%d", current, replicas)
+	}
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if currentRevision != updateRevision {
+		return InProgressStatus, "Waiting for updated revision to match current"
+	}
+	return CurrentStatus, fmt.Sprintf("All replicas scheduled as expected. Replicas: %d", ready)
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured) (Status, string) {
+	desired := statusInt(obj, "status", "desiredNumberScheduled")
+	scheduled := statusInt(obj, "status", "currentNumberScheduled")
+	updated := statusInt(obj, "status", "updatedNumberScheduled")
+	available := statusInt(obj, "status", "numberAvailable")
+	ready := statusInt(obj, "status", "numberReady")
+
+	switch {
+	case desired == 0 && statusInt(obj, "status", "observedGeneration") == 0:
+		return InProgressStatus, "Missing .status.desiredNumberScheduled"
+	case scheduled < desired:
+		return InProgressStatus, fmt.Sprintf("Scheduled: %d/%d", scheduled, desired)
+	case updated < desired:
+		return InProgressStatus, fmt.Sprintf("Updated: %d/%d", updated, desired)
+	case available < desired:
+		return InProgressStatus, fmt.Sprintf("Available: %d/%d", available, desired)
+	case ready < desired:
+		return InProgressStatus, fmt.Sprintf("Ready: %d/%d", ready, desired)
+	}
+	return CurrentStatus, fmt.Sprintf("All replicas scheduled as expected. Replicas: %d", desired)
+}
+
+func replicaSetStatus(obj *unstructured.Unstructured) (Status, string) {
+	replicas := specReplicas(obj)
+	labeled := statusInt(obj, "status", "fullyLabeledReplicas")
+	available := statusInt(obj, "status", "availableReplicas")
+	ready := statusInt(obj, "status", "readyReplicas")
+
+	c := findCondition(objectConditions(obj), "ReplicaFailure")
+	if c != nil && c["status"] == "True" {
+		return InProgressStatus, conditionMessage(c)
+	}
+	switch {
+	case labeled < replicas:
+		return InProgressStatus, fmt.Sprintf("Labelled: %d/%d", labeled, replicas)
+	case available < replicas:
+		return InProgressStatus, fmt.Sprintf("Available: %d/%d", available, replicas)
+	case ready < replicas:
+		return InProgressStatus, fmt.Sprintf("Ready: %d/%d", ready, replicas)
+	}
+	return CurrentStatus, fmt.Sprintf("ReplicaSet is available. Replicas: %d", replicas)
+}
+
+// jobStatus follows kstatus in treating a running Job as Current: it has
+// been reconciled, even though it has not completed yet.
+func jobStatus(obj *unstructured.Unstructured) (Status, string) {
+	conditions := objectConditions(obj)
+	if c := findCondition(conditions, "Failed"); c != nil && c["status"] == "True" {
+		return FailedStatus, conditionMessage(c)
+	}
+	succeeded := statusInt(obj, "status", "succeeded")
+	if c := findCondition(conditions, "Complete"); c != nil && c["status"] == "True" {
+		return CurrentStatus, fmt.Sprintf("Job completed. Succeeded: %d", succeeded)
+	}
+	if _, found, _ := unstructured.NestedString(obj.Object, "status", "startTime"); !found {
+		return InProgressStatus, "Job not started"
+	}
+	return CurrentStatus, fmt.Sprintf("Job in progress. Succeeded: %d, active: %d, failed: %d",
+		succeeded, statusInt(obj, "status", "active"), statusInt(obj, "status", "failed"))
+}
+
+func podStatus(obj *unstructured.Unstructured) (Status, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return CurrentStatus, "Pod has completed successfully"
+	case "Failed":
+		return FailedStatus, "Pod has completed, but not successfully"
+	}
+
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	for _, c := range containers {
+		reason, _, _ := unstructured.NestedString(c.(map[string]any), "state", "waiting", "reason")
+		if reason == "CrashLoopBackOff" {
+			name, _, _ := unstructured.NestedString(c.(map[string]any), "name")
+			return FailedStatus, fmt.Sprintf("Container %s is in CrashLoopBackOff", name)
+		}
+	}
+
+	c := findCondition(objectConditions(obj), "Ready")
+	if phase == "Running" && c != nil && c["status"] == "True" {
+		return CurrentStatus, "Pod is Ready"
+	}
+	if c := findCondition(objectConditions(obj), "PodScheduled"); c != nil &&
+		c["status"] == "False" {
+		return InProgressStatus, conditionMessage(c)
+	}
+	return InProgressStatus, fmt.Sprintf("Pod phase is %s", phase)
+}
+
+func pvcStatus(obj *unstructured.Unstructured) (Status, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase != "Bound" {
+		return InProgressStatus, fmt.Sprintf("PVC is not Bound. Phase: %s", phase)
+	}
+	return CurrentStatus, "PVC is Bound"
+}
+
+func serviceStatus(obj *unstructured.Unstructured) (Status, string) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType == "ExternalName" {
+		return CurrentStatus, "Service is ready"
+	}
+	clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP")
+	if clusterIP == "" {
+		return InProgressStatus, "ClusterIP not set"
+	}
+	if serviceType == "LoadBalancer" {
+		ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+		if len(ingress) == 0 {
+			return InProgressStatus, "Load balancer ingress not set"
+		}
+	}
+	return CurrentStatus, "Service is ready"
+}
+
+func crdStatus(obj *unstructured.Unstructured) (Status, string) {
+	conditions := objectConditions(obj)
+	if c := findCondition(conditions, "NamesAccepted"); c != nil && c["status"] == "False" {
+		return FailedStatus, conditionMessage(c)
+	}
+	if c := findCondition(conditions, "Established"); c != nil && c["status"] == "True" {
+		return CurrentStatus, "CRD is established"
+	}
+	return InProgressStatus, "CRD is not established"
+}
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func statusTestObject(apiVersion, kind string, fields map[string]any) *unstructured.Unstructured {
+	obj := map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": "test", "generation": int64(2)},
+	}
+	for k, v := range fields {
+		obj[k] = v
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func condition(conditionType, status string) map[string]any {
+	return map[string]any{"type": conditionType, "status": status, "reason": "Testing"}
+}
+
+func TestComputeStatus(t *testing.T) {
+	samples := map[string]struct {
+		obj      *unstructured.Unstructured
+		expected Status
+	}{
+		"custom resource without status": {
+			obj:      statusTestObject("example.com/v1", "Widget", nil),
+			expected: CurrentStatus,
+		},
+		"custom resource with stale observedGeneration": {
+			obj: statusTestObject("example.com/v1", "Widget", map[string]any{
+				"status": map[string]any{"observedGeneration": int64(1)},
+			}),
+			expected: InProgressStatus,
+		},
+		"custom resource reconciling": {
+			obj: statusTestObject("example.com/v1", "Widget", map[string]any{
+				"status": map[string]any{
+					"observedGeneration": int64(2),
+					"conditions":         []any{condition("Reconciling", "True")},
+				},
+			}),
+			expected: InProgressStatus,
+		},
+		"custom resource stalled": {
+			obj: statusTestObject("example.com/v1", "Widget", map[string]any{
+				"status": map[string]any{"conditions": []any{condition("Stalled", "True")}},
+			}),
+			expected: FailedStatus,
+		},
+		"custom resource not ready": {
+			obj: statusTestObject("example.com/v1", "Widget", map[string]any{
+				"status": map[string]any{"conditions": []any{condition("Ready", "False")}},
+			}),
+			expected: InProgressStatus,
+		},
+		"custom resource ready": {
+			obj: statusTestObject("example.com/v1", "Widget", map[string]any{
+				"status": map[string]any{"conditions": []any{condition("Ready", "True")}},
+			}),
+			expected: CurrentStatus,
+		},
+		"deployment updating": {
+			obj: statusTestObject("apps/v1", "Deployment", map[string]any{
+				"spec": map[string]any{"replicas": int64(3)},
+				"status": map[string]any{
+					"observedGeneration": int64(2),
+					"replicas":           int64(3),
+					"updatedReplicas":    int64(1),
+				},
+			}),
+			expected: InProgressStatus,
+		},
+		"deployment available": {
+			obj: statusTestObject("apps/v1", "Deployment", map[string]any{
+				"spec": map[string]any{"replicas": int64(3)},
+				"status": map[string]any{
+					"observedGeneration": int64(2),
+					"replicas":           int64(3),
+					"updatedReplicas":    int64(3),
+					"readyReplicas":      int64(3),
+					"availableReplicas":  int64(3),
+				},
+			}),
+			expected: CurrentStatus,
+		},
+		"job not started": {
+			obj:      statusTestObject("batch/v1", "Job", nil),
+			expected: InProgressStatus,
+		},
+		"job running": {
+			obj: statusTestObject("batch/v1", "Job", map[string]any{
+				"status": map[string]any{"startTime": "2024-01-01T00:00:00Z", "active": int64(1)},
+			}),
+			expected: CurrentStatus,
+		},
+		"job failed": {
+			obj: statusTestObject("batch/v1", "Job", map[string]any{
+				"status": map[string]any{"conditions": []any{condition("Failed", "True")}},
+			}),
+			expected: FailedStatus,
+		},
+		"pvc pending": {
+			obj: statusTestObject("v1", "PersistentVolumeClaim", map[string]any{
+				"status": map[string]any{"phase": "Pending"},
+			}),
+			expected: InProgressStatus,
+		},
+		"pvc bound": {
+			obj: statusTestObject("v1", "PersistentVolumeClaim", map[string]any{
+				"status": map[string]any{"phase": "Bound"},
+			}),
+			expected: CurrentStatus,
+		},
+		"load balancer without ingress": {
+			obj: statusTestObject("v1", "Service", map[string]any{
+				"spec": map[string]any{"type": "LoadBalancer", "clusterIP": "10.0.0.1"},
+			}),
+			expected: InProgressStatus,
+		},
+		"load balancer with ingress": {
+			obj: statusTestObject("v1", "Service", map[string]any{
+				"spec": map[string]any{"type": "LoadBalancer", "clusterIP": "10.0.0.1"},
+				"status": map[string]any{"loadBalancer": map[string]any{
+					"ingress": []any{map[string]any{"ip": "192.0.2.1"}},
+				}},
+			}),
+			expected: CurrentStatus,
+		},
+		"pod running and ready": {
+			obj: statusTestObject("v1", "Pod", map[string]any{
+				"status": map[string]any{
+					"phase":      "Running",
+					"conditions": []any{condition("Ready", "True")},
+				},
+			}),
+			expected: CurrentStatus,
+		},
+		"pod crash looping": {
+			obj: statusTestObject("v1", "Pod", map[string]any{
+				"status": map[string]any{
+					"phase": "Running",
+					"containerStatuses": []any{map[string]any{
+						"name": "app",
+						"state": map[string]any{
+							"waiting": map[string]any{"reason": "CrashLoopBackOff"},
+						},
+					}},
+				},
+			}),
+			expected: FailedStatus,
+		},
+		"crd established": {
+			obj: statusTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition",
+				map[string]any{"status": map[string]any{
+					"conditions": []any{condition("Established", "True")},
+				}}),
+			expected: CurrentStatus,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			if status, msg := ComputeStatus(s.obj); status != s.expected {
+				t.Fatalf("expected %s, got %s: %s", s.expected, status, msg)
+			}
+		})
+	}
+}
+
+func TestComputeStatusTerminating(t *testing.T) {
+	obj := statusTestObject("example.com/v1", "Widget", nil)
+	now := v1.Now()
+	obj.SetDeletionTimestamp(&now)
+	if status, _ := ComputeStatus(obj); status != TerminatingStatus {
+		t.Fatalf("expected %s, got %s", TerminatingStatus, status)
+	}
+}
+
+func TestReadyWaiterDone(t *testing.T) {
+	w := &ReadyWaiter{Logger: hclog.NewNullLogger()}
+	ready := statusTestObject("example.com/v1", "Widget", map[string]any{
+		"status": map[string]any{"conditions": []any{condition("Ready", "True")}},
+	})
+	if done, err := w.Done(ready); !done || err != nil {
+		t.Fatalf("expected a ready resource to be done, got %v, %v", done, err)
+	}
+	stalled := statusTestObject("example.com/v1", "Widget", map[string]any{
+		"status": map[string]any{"conditions": []any{condition("Stalled", "True")}},
+	})
+	if _, err := w.Done(stalled); err == nil {
+		t.Fatal("expected a stalled resource to fail the wait")
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ValidationError describes a manifest value that does not conform to the
+// OpenAPI schema of its resource type.
+type ValidationError struct {
+	Path    *tftypes.AttributePath
+	Summary string
+	Detail  string
+	// Warning marks errors that may not hold once the API server has applied
+	// defaults, which are reported as warnings rather than errors.
+	Warning bool
+}
+
+// rootImplicitFields are accepted at the top level of every object even when
+// a (CRD) schema does not declare them.
+var rootImplicitFields = map[string]bool{
+	"apiVersion": true,
+	"kind":       true,
+	"metadata":   true,
+}
+
+// ValidateAgainstSchema checks obj against sch and returns a ValidationError
+// for every unknown field, mistyped scalar, missing required property, and
+// violated enum, pattern, length or item-count constraint.
+//
+// Nil values are skipped: they stand for null or not-yet-known values in a
+// Terraform plan and are checked by the API server at apply time instead.
+func ValidateAgainstSchema(
+	obj map[string]any,
+	sch *openapi3.Schema,
+	defs map[string]*openapi3.SchemaRef,
+) []ValidationError {
+	v := &validator{defs: defs}
+	v.validate(obj, sch, tftypes.NewAttributePath(), 50)
+	return v.errs
+}
+
+type validator struct {
+	defs map[string]*openapi3.SchemaRef
+	errs []ValidationError
+}
+
+func (v *validator) addError(ap *tftypes.AttributePath, summary, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{
+		Path:    ap,
+		Summary: summary,
+		Detail:  fmt.Sprintf("%s: %s", FieldPathString(ap), fmt.Sprintf(format, args...)),
+	})
+}
+
+func (v *validator) validate(
+	val any,
+	sch *openapi3.Schema,
+	ap *tftypes.AttributePath,
+	depth int,
+) {
+	if val == nil || sch == nil || depth == 0 {
+		return
+	}
+
+	if isIntOrString(sch) {
+		switch val.(type) {
+		case string, float64, int, int64:
+		default:
+			v.addError(ap, "Invalid field type",
+				"expected integer or string, got %s", jsonTypeName(val))
+		}
+		return
+	}
+	if isQuantity(sch) {
+		switch val.(type) {
+		case string, float64, int, int64:
+		default:
+			v.addError(ap, "Invalid field type",
+				"expected number or string, got %s", jsonTypeName(val))
+		}
+		return
+	}
+
+	switch {
+	case sch.Type.Is(openapi3.TypeObject):
+		m, ok := val.(map[string]any)
+		if !ok {
+			v.addError(ap, "Invalid field type", "expected object, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateObject(m, sch, ap, depth)
+	case sch.Type.Is(openapi3.TypeArray):
+		l, ok := val.([]any)
+		if !ok {
+			v.addError(ap, "Invalid field type", "expected array, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateArray(l, sch, ap, depth)
+	case sch.Type.Is(openapi3.TypeString):
+		s, ok := val.(string)
+		if !ok {
+			v.addError(ap, "Invalid field type", "expected string, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateString(s, sch, ap)
+	case sch.Type.Is(openapi3.TypeInteger):
+		f, ok := toFloat(val)
+		if !ok || f != math.Trunc(f) {
+			v.addError(ap, "Invalid field type", "expected integer, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateEnum(val, sch, ap)
+	case sch.Type.Is(openapi3.TypeNumber):
+		if _, ok := toFloat(val); !ok {
+			v.addError(ap, "Invalid field type", "expected number, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateEnum(val, sch, ap)
+	case sch.Type.Is(openapi3.TypeBoolean):
+		if _, ok := val.(bool); !ok {
+			v.addError(ap, "Invalid field type", "expected boolean, got %s", jsonTypeName(val))
+			return
+		}
+		v.validateEnum(val, sch, ap)
+	default:
+		// untyped schemas (e.g. properties declared only by x-kubernetes-* extensions)
+		// with declared properties are treated as objects; anything else is free-form
+		if m, ok := val.(map[string]any); ok && len(sch.Properties) > 0 {
+			v.validateObject(m, sch, ap, depth)
+		}
+	}
+}
+
+func (v *validator) validateObject(
+	m map[string]any,
+	sch *openapi3.Schema,
+	ap *tftypes.AttributePath,
+	depth int,
+) {
+	preserveUnknown := extensionBool(sch.Extensions[PreserveUnknownFieldsLabel])
+	var additional *openapi3.Schema
+	if sch.AdditionalProperties.Schema != nil {
+		additional, _ = resolveSchemaRef(sch.AdditionalProperties.Schema, v.defs)
+	}
+	allowAny := preserveUnknown ||
+		additional != nil ||
+		len(sch.Properties) == 0 ||
+		(sch.AdditionalProperties.Has != nil && *sch.AdditionalProperties.Has)
+
+	for _, name := range sch.Required {
+		if _, ok := m[name]; !ok {
+			v.addError(ap, "Missing required field", "required field %q is not set", name)
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		kap := ap.WithAttributeName(k)
+		if pref, ok := sch.Properties[k]; ok {
+			ps, err := resolveSchemaRef(pref, v.defs)
+			if err == nil {
+				v.validate(m[k], ps, kap, depth-1)
+			}
+			continue
+		}
+		if additional != nil {
+			v.validate(m[k], additional, kap, depth-1)
+			continue
+		}
+		if allowAny || (len(ap.Steps()) == 0 && rootImplicitFields[k]) {
+			continue
+		}
+		v.addError(kap, "Unknown field", "field %q is not declared in the schema", k)
+	}
+}
+
+func (v *validator) validateArray(
+	l []any,
+	sch *openapi3.Schema,
+	ap *tftypes.AttributePath,
+	depth int,
+) {
+	if uint64(len(l)) < sch.MinItems {
+		v.addError(ap, "Too few items",
+			"must have at least %d items, got %d", sch.MinItems, len(l))
+	}
+	if sch.MaxItems != nil && uint64(len(l)) > *sch.MaxItems {
+		v.addError(ap, "Too many items",
+			"must have at most %d items, got %d", *sch.MaxItems, len(l))
+	}
+	if sch.Items == nil {
+		return
+	}
+	is, err := resolveSchemaRef(sch.Items, v.defs)
+	if err != nil {
+		return
+	}
+	for i, e := range l {
+		v.validate(e, is, ap.WithElementKeyInt(i), depth-1)
+	}
+}
+
+func (v *validator) validateString(s string, sch *openapi3.Schema, ap *tftypes.AttributePath) {
+	n := uint64(utf8.RuneCountInString(s))
+	if n < sch.MinLength {
+		v.addError(ap, "Value too short", "must be at least %d characters long", sch.MinLength)
+	}
+	if sch.MaxLength != nil && n > *sch.MaxLength {
+		v.addError(ap, "Value too long", "must be at most %d characters long", *sch.MaxLength)
+	}
+	if sch.Pattern != "" {
+		// patterns using ECMA-only syntax cannot be checked here and are left to the API server
+		if re, err := regexp.Compile(sch.Pattern); err == nil && !re.MatchString(s) {
+			v.addError(ap, "Value does not match pattern", "%q does not match %q", s, sch.Pattern)
+		}
+	}
+	v.validateEnum(s, sch, ap)
+}
+
+func (v *validator) validateEnum(val any, sch *openapi3.Schema, ap *tftypes.AttributePath) {
+	if len(sch.Enum) == 0 {
+		return
+	}
+	allowed := make([]string, 0, len(sch.Enum))
+	for _, e := range sch.Enum {
+		if scalarEqual(val, e) {
+			return
+		}
+		allowed = append(allowed, fmt.Sprintf("%v", e))
+	}
+	v.addError(ap, "Value not allowed", "%v is not one of: %s", val, strings.Join(allowed, ", "))
+}
+
+func isIntOrString(sch *openapi3.Schema) bool {
+	if sch.Format == "int-or-string" {
+		return true
+	}
+	return extensionBool(sch.Extensions["x-kubernetes-int-or-string"])
+}
+
+// isQuantity reports whether sch describes a resource.Quantity, which
+// OpenAPI v2 documents declare as a string although numbers are accepted.
+func isQuantity(sch *openapi3.Schema) bool {
+	return sch.Format == "quantity"
+}
+
+func scalarEqual(a, b any) bool {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		return af == bf
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64:
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// FieldPathString renders an attribute path in dotted form,
+// e.g. spec.containers[0].image.
+func FieldPathString(ap *tftypes.AttributePath) string {
+	var b strings.Builder
+	for _, s := range ap.Steps() {
+		switch st := s.(type) {
+		case tftypes.AttributeName:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(string(st))
+		case tftypes.ElementKeyString:
+			fmt.Fprintf(&b, "[%q]", string(st))
+		case tftypes.ElementKeyInt:
+			fmt.Fprintf(&b, "[%d]", int64(st))
+		}
+	}
+	return b.String()
+}
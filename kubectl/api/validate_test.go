@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var widgetGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+const validateTestSpec = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "io.example.Widget": {
+        "type": "object",
+        "x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+        "properties": {
+          "spec": {"allOf": [{"$ref": "#/components/schemas/io.example.WidgetSpec"}]}
+        }
+      },
+      "io.example.WidgetSpec": {
+        "type": "object",
+        "required": ["size"],
+        "properties": {
+          "replicas": {"type": "integer"},
+          "size": {"type": "string", "enum": ["small", "large"]},
+          "name": {"type": "string", "pattern": "^[a-z]+$", "maxLength": 5},
+          "port": {"x-kubernetes-int-or-string": true},
+          "maxSurge": {"type": "string", "format": "int-or-string"},
+          "memory": {"type": "string", "format": "quantity"},
+          "tags": {"type": "array", "minItems": 1, "items": {"type": "string"}},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "extra": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+        }
+      }
+    }
+  }
+}`
+
+func widgetSchema(t *testing.T) (*openapi3.Schema, map[string]*openapi3.SchemaRef) {
+	t.Helper()
+	f, err := NewFoundryFromSpecV3([]byte(validateTestSpec))
+	if err != nil {
+		t.Fatalf("foundry: %s", err)
+	}
+	sch, defs, err := f.GetSchemaByGVK(widgetGVK)
+	if err != nil {
+		t.Fatalf("schema: %s", err)
+	}
+	return sch, defs
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	sch, defs := widgetSchema(t)
+
+	samples := map[string]struct {
+		spec     map[string]any
+		expected []string
+	}{
+		"valid": {
+			spec: map[string]any{
+				"replicas": float64(3),
+				"size":     "small",
+				"name":     "abc",
+				"port":     "http",
+				"tags":     []any{"a"},
+				"labels":   map[string]any{"app": "x"},
+				"extra":    map[string]any{"anything": []any{true}},
+			},
+		},
+		"numbers for v2 int-or-string and quantity": {
+			spec: map[string]any{
+				"size":     "small",
+				"maxSurge": float64(1),
+				"memory":   float64(0.5),
+			},
+		},
+		"strings for v2 int-or-string and quantity": {
+			spec: map[string]any{"size": "small", "maxSurge": "25%", "memory": "512Mi"},
+		},
+		"quantity of wrong type": {
+			spec:     map[string]any{"size": "small", "memory": true},
+			expected: []string{"spec.memory: expected number or string, got boolean"},
+		},
+		"int-or-string of wrong type": {
+			spec:     map[string]any{"size": "small", "maxSurge": []any{}},
+			expected: []string{"spec.maxSurge: expected integer or string, got array"},
+		},
+		"unknown values are skipped": {
+			spec: map[string]any{"size": nil, "replicas": nil, "tags": []any{nil}},
+		},
+		"unknown field": {
+			spec:     map[string]any{"size": "small", "replica": float64(1)},
+			expected: []string{"spec.replica: field \"replica\" is not declared in the schema"},
+		},
+		"wrong scalar type": {
+			spec:     map[string]any{"size": "small", "replicas": "3"},
+			expected: []string{"spec.replicas: expected integer, got string"},
+		},
+		"fractional integer": {
+			spec:     map[string]any{"size": "small", "replicas": 1.5},
+			expected: []string{"spec.replicas: expected integer, got number"},
+		},
+		"missing required": {
+			spec:     map[string]any{},
+			expected: []string{"spec: required field \"size\" is not set"},
+		},
+		"enum": {
+			spec:     map[string]any{"size": "medium"},
+			expected: []string{"spec.size: medium is not one of: small, large"},
+		},
+		"pattern and maxLength": {
+			spec: map[string]any{"size": "small", "name": "ABCDEF"},
+			expected: []string{
+				"spec.name: \"ABCDEF\" does not match \"^[a-z]+$\"",
+				"spec.name: must be at most 5 characters long",
+			},
+		},
+		"minItems": {
+			spec:     map[string]any{"size": "small", "tags": []any{}},
+			expected: []string{"spec.tags: must have at least 1 items, got 0"},
+		},
+		"array items": {
+			spec:     map[string]any{"size": "small", "tags": []any{"a", true}},
+			expected: []string{"spec.tags[1]: expected string, got boolean"},
+		},
+		"additionalProperties": {
+			spec:     map[string]any{"size": "small", "labels": map[string]any{"n": float64(1)}},
+			expected: []string{"spec.labels.n: expected string, got number"},
+		},
+	}
+
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			obj := map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]any{"name": "w"},
+				"spec":       s.spec,
+			}
+			errs := ValidateAgainstSchema(obj, sch, defs)
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				got = append(got, e.Detail)
+			}
+			sort.Strings(got)
+			expected := append([]string{}, s.expected...)
+			sort.Strings(expected)
+			if len(got) != len(expected) {
+				t.Fatalf("expected %q, got %q", expected, got)
+			}
+			for i := range got {
+				if got[i] != expected[i] {
+					t.Fatalf("expected %q, got %q", expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchemaPaths(t *testing.T) {
+	sch, defs := widgetSchema(t)
+	obj := map[string]any{
+		"spec": map[string]any{"size": "small", "tags": []any{"a", float64(1)}},
+	}
+	errs := ValidateAgainstSchema(obj, sch, defs)
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+	expected := `AttributeName("spec").AttributeName("tags").ElementKeyInt(1)`
+	if got := errs[0].Path.String(); got != expected {
+		t.Fatalf("unexpected path %s", got)
+	}
+}
@@ -9,6 +9,7 @@
 	"fmt"
 	"math/big"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/payload"
@@ -17,8 +18,7 @@
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/zclconf/go-cty/cty"
-	"k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/kubectl/pkg/polymorphichelpers"
 )
@@ -31,6 +31,25 @@ type Waiter interface {
 	Wait(context.Context) error
 }
 
+// ObjectWaiter is a Waiter that decides from a single read of the object it
+// waits on whether the wait is done, so that other checks can run on the
+// same reads.
+type ObjectWaiter interface {
+	Waiter
+	Done(obj *unstructured.Unstructured) (bool, error)
+	// Reason describes what the waiter waits on, for the WaiterError of a
+	// wait that times out.
+	Reason() string
+}
+
+// TimeoutExplainer is implemented by ObjectWaiters that can tell why a wait
+// timed out, e.g. from the last error they ignored while waiting.
+type TimeoutExplainer interface {
+	// TimeoutError returns err, the error the wait ended with, with the
+	// explanation added when it is a WaiterError.
+	TimeoutError(err error) error
+}
+
 // WaiterError represents a timeout error while waiting for a condition.
 type WaiterError struct {
 	Reason string
@@ -41,10 +60,37 @@ func (e WaiterError) Error() string {
 	return fmt.Sprintf("timed out waiting on %v", e.Reason)
 }
 
+// Operators a FieldMatcher compares a field with.
+const (
+	FieldEq        = "eq"
+	FieldNe        = "ne"
+	FieldRegex     = "regex"
+	FieldExists    = "exists"
+	FieldNotExists = "not_exists"
+	FieldGt        = "gt"
+	FieldGte       = "gte"
+	FieldLt        = "lt"
+	FieldLte       = "lte"
+)
+
 // FieldMatcher contains a tftypes.AttributePath to a field and a regexp to match on it.
+//
+// Operator changes how the field is compared: eq, ne and regex match the
+// field against ValueMatcher, exists and not_exists only check whether it is
+// set, and gt, gte, lt and lte compare it numerically with Number. An empty
+// Operator matches ValueMatcher.
+//
+// When the path contains splats, Path leads to the list expanded by the
+// first splat and Splats holds the path within its elements up to the next
+// splat, and so on. The field then matches if it does in all of the elements
+// of the lists, or, with Any, in one of them.
 type FieldMatcher struct {
 	Path         *tftypes.AttributePath
+	Splats       []*tftypes.AttributePath
 	ValueMatcher *regexp.Regexp
+	Operator     string
+	Number       *big.Float
+	Any          bool
 }
 
 // ConditionMatcher describes a condition type/status pair to wait for.
@@ -58,13 +104,16 @@ type ConditionMatcher struct {
 func NewResourceWaiterFromConfig(
 	resource dynamic.ResourceInterface,
 	resourceName string,
+	generation int64,
 	resourceType tftypes.Type,
 	typeHints map[string]string,
 	rollout bool,
+	ready bool,
 	fieldMatchers []FieldMatcher,
 	conditions []ConditionMatcher,
+	expression *Expression,
 	logger hclog.Logger,
-) Waiter {
+) ObjectWaiter {
 	if rollout {
 		return &RolloutWaiter{
 			Resource:     resource,
@@ -73,10 +122,19 @@ func NewResourceWaiterFromConfig(
 		}
 	}
 
+	if ready {
+		return &ReadyWaiter{
+			Resource:     resource,
+			ResourceName: resourceName,
+			Logger:       logger,
+		}
+	}
+
 	if len(conditions) > 0 {
 		return &ConditionsWaiterV2{
 			Resource:     resource,
 			ResourceName: resourceName,
+			Generation:   generation,
 			Conditions:   conditions,
 			Logger:       logger,
 		}
@@ -86,6 +144,7 @@ func NewResourceWaiterFromConfig(
 		return &FieldWaiter{
 			Resource:      resource,
 			ResourceName:  resourceName,
+			Generation:    generation,
 			ResourceType:  resourceType,
 			TypeHints:     typeHints,
 			FieldMatchers: fieldMatchers,
@@ -93,6 +152,15 @@ func NewResourceWaiterFromConfig(
 		}
 	}
 
+	if expression != nil {
+		return &ExpressionWaiter{
+			Resource:     resource,
+			ResourceName: resourceName,
+			Expression:   expression,
+			Logger:       logger,
+		}
+	}
+
 	return &NoopWaiter{}
 }
 
@@ -182,10 +250,12 @@ func NewResourceWaiter(
 }
 
 // FieldWaiter will wait for a set of fields to be set,
-// or have a particular value.
+// or have a particular value. When Generation is set, fields only match once
+// the controller has observed that generation of the resource.
 type FieldWaiter struct {
 	Resource      dynamic.ResourceInterface
 	ResourceName  string
+	Generation    int64
 	ResourceType  tftypes.Type
 	TypeHints     map[string]string
 	FieldMatchers []FieldMatcher
@@ -195,85 +265,154 @@ type FieldWaiter struct {
 // Wait blocks until all of the FieldMatchers configured evaluate to true.
 func (w *FieldWaiter) Wait(ctx context.Context) error {
 	w.Logger.Info("[Wait] Waiting until fields match...\n")
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "field matchers"}
-			}
-		}
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err == nil {
+		w.Logger.Info("[Wait] Done waiting.\n")
+	}
+	return err
+}
 
-		res, err := w.Resource.Get(ctx, w.ResourceName, v1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
-		}
-		resObj := res.Object
-		if meta, ok := resObj["metadata"].(map[string]any); ok {
-			delete(meta, "managedFields")
+// Reason describes what the waiter waits on.
+func (w *FieldWaiter) Reason() string {
+	return "field matchers"
+}
+
+// Done reports whether all of the FieldMatchers evaluate to true on res.
+func (w *FieldWaiter) Done(res *unstructured.Unstructured) (bool, error) {
+	if !generationObserved(res, w.Generation) {
+		return false, nil
+	}
+
+	resObj := res.DeepCopy().Object
+	if meta, ok := resObj["metadata"].(map[string]any); ok {
+		delete(meta, "managedFields")
+	}
+
+	w.Logger.Trace("[Wait]", "API Response", resObj)
+
+	obj, err := payload.ToTFValue(
+		resObj,
+		w.ResourceType,
+		w.TypeHints,
+		tftypes.NewAttributePath(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	for _, m := range w.FieldMatchers {
+		if done, err := m.match(obj); !done || err != nil {
+			return done, err
 		}
+	}
 
-		w.Logger.Trace("[Wait]", "API Response", resObj)
+	return true, nil
+}
 
-		obj, err := payload.ToTFValue(
-			resObj,
-			w.ResourceType,
-			w.TypeHints,
-			tftypes.NewAttributePath(),
-		)
+// match reports whether the field of m matches in obj.
+func (m FieldMatcher) match(obj tftypes.Value) (bool, error) {
+	values := fieldValues(obj, append([]*tftypes.AttributePath{m.Path}, m.Splats...))
+	if len(values) == 0 {
+		// The list the splat expands is empty or not set yet.
+		return false, nil
+	}
+	for _, v := range values {
+		matched, err := m.matchValue(v)
 		if err != nil {
-			return err
+			return true, err
+		}
+		if matched == m.Any {
+			return matched, nil
 		}
+	}
+	return !m.Any, nil
+}
 
-		done, err := func(obj tftypes.Value) (bool, error) {
-			for _, m := range w.FieldMatchers {
-				vi, rp, err := tftypes.WalkAttributePath(obj, m.Path)
-				if err != nil {
-					return false, err
-				}
-				if len(rp.Steps()) > 0 {
-					return false, fmt.Errorf("attribute not present at path '%s'", m.Path.String())
-				}
+// matchValue reports whether v, the field or nil when it is not set,
+// matches.
+func (m FieldMatcher) matchValue(v *tftypes.Value) (bool, error) {
+	switch m.Operator {
+	case FieldExists:
+		return v != nil, nil
+	case FieldNotExists:
+		return v == nil, nil
+	}
+	if v == nil {
+		return false, nil
+	}
 
-				var s string
-				v := vi.(tftypes.Value)
-				switch {
-				case v.Type().Is(tftypes.String):
-					_ = v.As(&s)
-				case v.Type().Is(tftypes.Bool):
-					var vb bool
-					_ = v.As(&vb)
-					s = fmt.Sprintf("%t", vb)
-				case v.Type().Is(tftypes.Number):
-					var f big.Float
-					_ = v.As(&f)
-					if f.IsInt() {
-						i, _ := f.Int64()
-						s = fmt.Sprintf("%d", i)
-					} else {
-						i, _ := f.Float64()
-						s = fmt.Sprintf("%f", i)
-					}
-				default:
-					return true, fmt.Errorf("wait_for: cannot match on type %q", v.Type().String())
-				}
+	switch m.Operator {
+	case FieldGt, FieldGte, FieldLt, FieldLte:
+		if !v.Type().Is(tftypes.Number) {
+			return false, nil
+		}
+		var f big.Float
+		_ = v.As(&f)
+		c := f.Cmp(m.Number)
+		switch m.Operator {
+		case FieldGt:
+			return c > 0, nil
+		case FieldGte:
+			return c >= 0, nil
+		case FieldLt:
+			return c < 0, nil
+		}
+		return c <= 0, nil
+	}
 
-				if !m.ValueMatcher.Match([]byte(s)) {
-					return false, nil
-				}
-			}
+	var s string
+	switch {
+	case v.Type().Is(tftypes.String):
+		_ = v.As(&s)
+	case v.Type().Is(tftypes.Bool):
+		var vb bool
+		_ = v.As(&vb)
+		s = fmt.Sprintf("%t", vb)
+	case v.Type().Is(tftypes.Number):
+		var f big.Float
+		_ = v.As(&f)
+		if f.IsInt() {
+			i, _ := f.Int64()
+			s = fmt.Sprintf("%d", i)
+		} else {
+			i, _ := f.Float64()
+			s = fmt.Sprintf("%f", i)
+		}
+	default:
+		return true, fmt.Errorf("wait_for: cannot match on type %q", v.Type().String())
+	}
 
-			return true, nil
-		}(obj)
+	matched := m.ValueMatcher.Match([]byte(s))
+	if m.Operator == FieldNe {
+		return !matched, nil
+	}
+	return matched, nil
+}
 
-		if done {
-			w.Logger.Info("[Wait] Done waiting.\n")
-			return err
+// fieldValues returns the values in v at paths, which are separated by
+// splats, with one value per element the splats expand to. A nil value
+// stands for a field that is not set.
+func fieldValues(v tftypes.Value, paths []*tftypes.AttributePath) []*tftypes.Value {
+	vi, rp, err := tftypes.WalkAttributePath(v, paths[0])
+	var field *tftypes.Value
+	if err == nil && len(rp.Steps()) == 0 {
+		if fv := vi.(tftypes.Value); !fv.IsNull() {
+			field = &fv
 		}
+	}
+	if len(paths) == 1 {
+		return []*tftypes.Value{field}
+	}
 
-		time.Sleep(WaiterSleepTime)
+	var elements []tftypes.Value
+	if field == nil || !field.IsKnown() || field.As(&elements) != nil {
+		return nil
+	}
+	var values []*tftypes.Value
+	for _, e := range elements {
+		values = append(values, fieldValues(e, paths[1:])...)
 	}
+	return values
 }
 
 // NoopWaiter is a placeholder for when there is nothing to wait on.
@@ -284,11 +423,67 @@ func (w *NoopWaiter) Wait(_ context.Context) error {
 	return nil
 }
 
+// Reason describes what the waiter waits on.
+func (w *NoopWaiter) Reason() string {
+	return "nothing"
+}
+
+// Done is always true.
+func (w *NoopWaiter) Done(_ *unstructured.Unstructured) (bool, error) {
+	return true, nil
+}
+
 // FieldPathToTftypesPath takes a string representation of
 // a path to a field in dot/square bracket notation
 // and returns a tftypes.AttributePath.
 func FieldPathToTftypesPath(fieldPath string) (*tftypes.AttributePath, error) {
-	t, d := hclsyntax.ParseTraversalAbs([]byte(fieldPath), "", hcl.Pos{Line: 1, Column: 1})
+	paths, err := FieldPathToTftypesPaths(fieldPath)
+	if err != nil {
+		return tftypes.NewAttributePath(), err
+	}
+	if len(paths) > 1 {
+		return tftypes.NewAttributePath(), fmt.Errorf("splat is not supported")
+	}
+	return paths[0], nil
+}
+
+// FieldPathToTftypesPaths is FieldPathToTftypesPath for paths that may
+// contain splats ([*]). It returns the tftypes.AttributePath of each part of
+// the path between splats.
+func FieldPathToTftypesPaths(fieldPath string) ([]*tftypes.AttributePath, error) {
+	var paths []*tftypes.AttributePath
+	for i, part := range strings.Split(fieldPath, "[*]") {
+		if i > 0 {
+			if part == "" {
+				paths = append(paths, tftypes.NewAttributePath())
+				continue
+			}
+			if part[0] != '.' && part[0] != '[' {
+				return nil, fmt.Errorf("invalid field path %q: splat must be followed by "+
+					"an attribute or index", fieldPath)
+			}
+			// The part is relative to the elements of the list, so give it a
+			// root to parse it.
+			part = "_" + part
+		}
+		p, err := traversalToTftypesPath(fieldPath, part, i > 0)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// traversalToTftypesPath parses part of fieldPath, which contains no splats,
+// and returns its tftypes.AttributePath, without its root when skipRoot is
+// set.
+func traversalToTftypesPath(
+	fieldPath string,
+	part string,
+	skipRoot bool,
+) (*tftypes.AttributePath, error) {
+	t, d := hclsyntax.ParseTraversalAbs([]byte(part), "", hcl.Pos{Line: 1, Column: 1})
 	if d.HasErrors() {
 		return tftypes.NewAttributePath(), fmt.Errorf(
 			"invalid field path %q: %s",
@@ -301,7 +496,9 @@ func FieldPathToTftypesPath(fieldPath string) (*tftypes.AttributePath, error) {
 	for _, p := range t {
 		switch t := p.(type) {
 		case hcl.TraverseRoot:
-			path = path.WithAttributeName(t.Name)
+			if !skipRoot {
+				path = path.WithAttributeName(t.Name)
+			}
 		case hcl.TraverseIndex:
 			indexKey := p.(hcl.TraverseIndex).Key
 			indexKeyType := indexKey.Type()
@@ -344,49 +541,82 @@ type RolloutWaiter struct {
 // Wait uses StatusViewer to determine if the rollout is done.
 func (w *RolloutWaiter) Wait(ctx context.Context) error {
 	w.Logger.Info("[Wait] Waiting until rollout complete...\n")
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "rollout to complete"}
-			}
-		}
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err != nil {
+		return err
+	}
 
-		res, err := w.Resource.Get(ctx, w.ResourceName, v1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
-		}
+	w.Logger.Info("[Wait] Rollout complete\n")
+	return nil
+}
 
-		gk := res.GetObjectKind().GroupVersionKind().GroupKind()
-		statusViewer, err := polymorphichelpers.StatusViewerFor(gk)
-		if err != nil {
-			return fmt.Errorf("error getting resource status: %v", err)
-		}
+// Reason describes what the waiter waits on.
+func (w *RolloutWaiter) Reason() string {
+	return "rollout to complete"
+}
 
-		_, done, err := statusViewer.Status(res, 0)
-		if err != nil {
-			return fmt.Errorf("error getting resource status: %v", err)
-		}
+// Done reports whether the rollout of res is complete.
+func (w *RolloutWaiter) Done(res *unstructured.Unstructured) (bool, error) {
+	gk := res.GetObjectKind().GroupVersionKind().GroupKind()
+	statusViewer, err := polymorphichelpers.StatusViewerFor(gk)
+	if err != nil {
+		return false, fmt.Errorf("error getting resource status: %v", err)
+	}
 
-		if done {
-			break
-		}
+	_, done, err := statusViewer.Status(res, 0)
+	if err != nil {
+		return false, fmt.Errorf("error getting resource status: %v", err)
+	}
+	return done, nil
+}
+
+// ReadyWaiter will wait for a resource of any kind to become ready, as
+// computed by ComputeStatus.
+type ReadyWaiter struct {
+	Resource     dynamic.ResourceInterface
+	ResourceName string
+	Logger       hclog.Logger
+}
 
-		time.Sleep(WaiterSleepTime)
+// Wait blocks until the resource is Current.
+func (w *ReadyWaiter) Wait(ctx context.Context) error {
+	w.Logger.Info("[Wait] Waiting until resource is ready...\n")
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err != nil {
+		return err
 	}
 
-	w.Logger.Info("[Wait] Rollout complete\n")
+	w.Logger.Info("[Wait] Resource is ready\n")
 	return nil
 }
 
+// Reason describes what the waiter waits on.
+func (w *ReadyWaiter) Reason() string {
+	return "resource to become ready"
+}
+
+// Done reports whether res is Current. A Failed resource ends the wait with
+// an error.
+func (w *ReadyWaiter) Done(res *unstructured.Unstructured) (bool, error) {
+	status, msg := ComputeStatus(res)
+	switch status {
+	case CurrentStatus:
+		return true, nil
+	case FailedStatus:
+		return false, fmt.Errorf("resource failed: %s", msg)
+	}
+	w.Logger.Debug(fmt.Sprintf("[Wait] Resource is %s: %s", status, msg))
+	return false, nil
+}
+
 // ConditionsWaiterV2 will wait for the specified conditions on
 // the resource to be met, using ConditionMatcher values.
-// Used by the terraform-plugin-framework resource.
+// Used by the terraform-plugin-framework resource. When Generation is set,
+// conditions reported for an older generation of the resource do not match.
 type ConditionsWaiterV2 struct {
 	Resource     dynamic.ResourceInterface
 	ResourceName string
+	Generation   int64
 	Conditions   []ConditionMatcher
 	Logger       hclog.Logger
 }
@@ -394,49 +624,37 @@ type ConditionsWaiterV2 struct {
 // Wait checks all the configured conditions have been met.
 func (w *ConditionsWaiterV2) Wait(ctx context.Context) error {
 	w.Logger.Info("[Wait] Waiting for conditions...\n")
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err != nil {
+		return err
+	}
 
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "conditions"}
-			}
-		}
+	w.Logger.Info("[Wait] All conditions met.\n")
+	return nil
+}
 
-		res, err := w.Resource.Get(ctx, w.ResourceName, v1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
-		}
+// Reason describes what the waiter waits on.
+func (w *ConditionsWaiterV2) Reason() string {
+	return "conditions"
+}
 
-		if status, ok := res.Object["status"].(map[string]any); ok {
-			if conditions, ok := status["conditions"].([]any); ok && len(conditions) > 0 {
-				conditionsMet := true
-				for _, c := range w.Conditions {
-					conditionMet := false
-					for _, cc := range conditions {
-						ccc, ok := cc.(map[string]any)
-						if !ok {
-							continue
-						}
-						if ccc["type"].(string) == c.Type {
-							conditionMet = ccc["status"].(string) == c.Status
-							break
-						}
-					}
-					conditionsMet = conditionsMet && conditionMet
-				}
-				if conditionsMet {
-					break
-				}
-			}
+// Done reports whether res has all of the configured conditions.
+func (w *ConditionsWaiterV2) Done(res *unstructured.Unstructured) (bool, error) {
+	if !generationObserved(res, w.Generation) {
+		return false, nil
+	}
+	conditions := objectConditions(res)
+	if len(conditions) == 0 {
+		return false, nil
+	}
+	for _, c := range w.Conditions {
+		condition := findCondition(conditions, c.Type)
+		if condition == nil || condition["status"] != c.Status ||
+			!conditionObserved(condition, w.Generation) {
+			return false, nil
 		}
-		time.Sleep(WaiterSleepTime)
 	}
-
-	w.Logger.Info("[Wait] All conditions met.\n")
-	return nil
+	return true, nil
 }
 
 // ConditionsWaiter will wait for the specified conditions on
@@ -452,49 +670,72 @@ type ConditionsWaiter struct {
 // Wait checks all the configured conditions have been met.
 func (w *ConditionsWaiter) Wait(ctx context.Context) error {
 	w.Logger.Info("[Wait] Waiting for conditions...\n")
+	err := WaitForObject(ctx, w.Resource, w.ResourceName, w.Reason(), w.Done)
+	if err != nil {
+		return err
+	}
 
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "conditions"}
-			}
-		}
+	w.Logger.Info("[Wait] All conditions met.\n")
+	return nil
+}
 
-		res, err := w.Resource.Get(ctx, w.ResourceName, v1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
+// Reason describes what the waiter waits on.
+func (w *ConditionsWaiter) Reason() string {
+	return "conditions"
+}
+
+// Done reports whether res has all of the configured conditions.
+func (w *ConditionsWaiter) Done(res *unstructured.Unstructured) (bool, error) {
+	conditions := objectConditions(res)
+	if len(conditions) == 0 {
+		return false, nil
+	}
+	for _, c := range w.Conditions {
+		var condition map[string]tftypes.Value
+		_ = c.As(&condition)
+		var conditionType, conditionStatus string
+		_ = condition["type"].As(&conditionType)
+		_ = condition["status"].As(&conditionStatus)
+		if !hasCondition(conditions, conditionType, conditionStatus) {
+			return false, nil
 		}
+	}
+	return true, nil
+}
 
-		if status, ok := res.Object["status"].(map[string]any); ok {
-			if conditions, ok := status["conditions"].([]any); ok && len(conditions) > 0 {
-				conditionsMet := true
-				for _, c := range w.Conditions {
-					var condition map[string]tftypes.Value
-					_ = c.As(&condition)
-					var conditionType, conditionStatus string
-					_ = condition["type"].As(&conditionType)
-					_ = condition["status"].As(&conditionStatus)
-					conditionMet := false
-					for _, cc := range conditions {
-						ccc := cc.(map[string]any)
-						if ccc["type"].(string) == conditionType {
-							conditionMet = ccc["status"].(string) == conditionStatus
-							break
-						}
-					}
-					conditionsMet = conditionsMet && conditionMet
-				}
-				if conditionsMet {
-					break
-				}
-			}
+// objectConditions returns the status.conditions of res.
+func objectConditions(res *unstructured.Unstructured) []any {
+	conditions, _, _ := unstructured.NestedSlice(res.Object, "status", "conditions")
+	return conditions
+}
+
+// hasCondition reports whether the first condition of conditionType in
+// conditions has the given status.
+func hasCondition(conditions []any, conditionType, status string) bool {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		if t, _ := condition["type"].(string); t == conditionType {
+			s, _ := condition["status"].(string)
+			return s == status
 		}
-		time.Sleep(WaiterSleepTime)
 	}
+	return false
+}
 
-	w.Logger.Info("[Wait] All conditions met.\n")
-	return nil
+// generationObserved reports whether the controller of res has observed
+// generation, going by status.observedGeneration. A resource that does not
+// report one is assumed to have, as is a generation of 0.
+func generationObserved(res *unstructured.Unstructured, generation int64) bool {
+	observed, found, _ := unstructured.NestedInt64(res.Object, "status", "observedGeneration")
+	return generation == 0 || !found || observed >= generation
+}
+
+// conditionObserved reports whether condition was set for generation or a
+// later one, going by its own observedGeneration when it has one.
+func conditionObserved(condition map[string]any, generation int64) bool {
+	observed, found, _ := unstructured.NestedInt64(condition, "observedGeneration")
+	return generation == 0 || !found || observed >= generation
 }
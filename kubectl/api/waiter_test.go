@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"math/big"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func generationTestObject(observed any, ready map[string]any) *unstructured.Unstructured {
+	status := map[string]any{"phase": "Running", "conditions": []any{ready}}
+	if observed != nil {
+		status["observedGeneration"] = observed
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]any{"name": "test", "generation": int64(3)},
+		"status":     status,
+	}}
+}
+
+func TestConditionsWaiterV2Generation(t *testing.T) {
+	samples := map[string]struct {
+		generation int64
+		obj        *unstructured.Unstructured
+		expected   bool
+	}{
+		"generation not tracked": {
+			obj:      generationTestObject(int64(2), condition("Ready", "True")),
+			expected: true,
+		},
+		"stale status": {
+			generation: 3,
+			obj:        generationTestObject(int64(2), condition("Ready", "True")),
+		},
+		"observed status": {
+			generation: 3,
+			obj:        generationTestObject(int64(3), condition("Ready", "True")),
+			expected:   true,
+		},
+		"stale condition": {
+			generation: 3,
+			obj: generationTestObject(nil, map[string]any{
+				"type": "Ready", "status": "True", "observedGeneration": int64(2),
+			}),
+		},
+		"observed condition": {
+			generation: 3,
+			obj: generationTestObject(nil, map[string]any{
+				"type": "Ready", "status": "True", "observedGeneration": int64(3),
+			}),
+			expected: true,
+		},
+		"no observed generation": {
+			generation: 3,
+			obj:        generationTestObject(nil, condition("Ready", "True")),
+			expected:   true,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			w := &ConditionsWaiterV2{
+				Generation: s.generation,
+				Conditions: []ConditionMatcher{{Type: "Ready", Status: "True"}},
+				Logger:     hclog.NewNullLogger(),
+			}
+			done, err := w.Done(s.obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if done != s.expected {
+				t.Fatalf("expected done to be %t, got %t", s.expected, done)
+			}
+		})
+	}
+}
+
+func TestFieldWaiterGeneration(t *testing.T) {
+	w := &FieldWaiter{
+		Generation:   3,
+		ResourceType: tftypes.DynamicPseudoType,
+		FieldMatchers: []FieldMatcher{{
+			Path: tftypes.NewAttributePath().
+				WithAttributeName("status").
+				WithAttributeName("phase"),
+			ValueMatcher: regexp.MustCompile("^Running$"),
+		}},
+		Logger: hclog.NewNullLogger(),
+	}
+	if done, err := w.Done(generationTestObject(int64(2), condition("Ready", "True"))); done ||
+		err != nil {
+		t.Fatalf("expected a stale status not to match, got %t, %v", done, err)
+	}
+	if done, err := w.Done(generationTestObject(int64(3), condition("Ready", "True"))); !done ||
+		err != nil {
+		t.Fatalf("expected an observed status to match, got %t, %v", done, err)
+	}
+}
+
+func TestFieldWaiterOperators(t *testing.T) {
+	res := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "test"},
+		"status": map[string]any{
+			"readyReplicas": int64(3),
+			"phase":         "Running",
+			"loadBalancer": map[string]any{"ingress": []any{
+				map[string]any{"ip": "192.0.2.1"},
+				map[string]any{"hostname": "lb.example.com"},
+			}},
+		},
+	}}
+	samples := map[string]struct {
+		key      string
+		matcher  FieldMatcher
+		expected bool
+	}{
+		"exists": {
+			key:      "status.phase",
+			matcher:  FieldMatcher{Operator: FieldExists},
+			expected: true,
+		},
+		"exists missing": {
+			key:     "status.podIP",
+			matcher: FieldMatcher{Operator: FieldExists},
+		},
+		"not_exists": {
+			key:      "status.podIP",
+			matcher:  FieldMatcher{Operator: FieldNotExists},
+			expected: true,
+		},
+		"ne": {
+			key: "status.phase",
+			matcher: FieldMatcher{
+				Operator:     FieldNe,
+				ValueMatcher: regexp.MustCompile("^Pending$"),
+			},
+			expected: true,
+		},
+		"ne equal": {
+			key:     "status.phase",
+			matcher: FieldMatcher{Operator: FieldNe, ValueMatcher: regexp.MustCompile("^Running$")},
+		},
+		"gte": {
+			key:      "status.readyReplicas",
+			matcher:  FieldMatcher{Operator: FieldGte, Number: big.NewFloat(3)},
+			expected: true,
+		},
+		"gt": {
+			key:     "status.readyReplicas",
+			matcher: FieldMatcher{Operator: FieldGt, Number: big.NewFloat(3)},
+		},
+		"lt": {
+			key:      "status.readyReplicas",
+			matcher:  FieldMatcher{Operator: FieldLt, Number: big.NewFloat(4)},
+			expected: true,
+		},
+		"numeric on string": {
+			key:     "status.phase",
+			matcher: FieldMatcher{Operator: FieldLte, Number: big.NewFloat(4)},
+		},
+		"all elements": {
+			key:     "status.loadBalancer.ingress[*].hostname",
+			matcher: FieldMatcher{Operator: FieldExists},
+		},
+		"any element": {
+			key:      "status.loadBalancer.ingress[*].hostname",
+			matcher:  FieldMatcher{Operator: FieldExists, Any: true},
+			expected: true,
+		},
+		"all elements match": {
+			key:      "status.loadBalancer.ingress[*]",
+			matcher:  FieldMatcher{Operator: FieldExists},
+			expected: true,
+		},
+		"splat over empty list": {
+			key:     "status.conditions[*].type",
+			matcher: FieldMatcher{Operator: FieldNotExists},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			paths, err := FieldPathToTftypesPaths(s.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := s.matcher
+			m.Path, m.Splats = paths[0], paths[1:]
+			w := &FieldWaiter{
+				ResourceType:  tftypes.DynamicPseudoType,
+				FieldMatchers: []FieldMatcher{m},
+				Logger:        hclog.NewNullLogger(),
+			}
+			done, err := w.Done(res)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if done != s.expected {
+				t.Fatalf("expected done to be %t, got %t", s.expected, done)
+			}
+		})
+	}
+}
+
+func TestFieldPathToTftypesPaths(t *testing.T) {
+	paths, err := FieldPathToTftypesPaths("status.loadBalancer.ingress[*].ports[0].port")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("loadBalancer").
+			WithAttributeName("ingress"),
+		tftypes.NewAttributePath().WithAttributeName("ports").WithElementKeyInt(0).
+			WithAttributeName("port"),
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %d paths, got %v", len(expected), paths)
+	}
+	for i := range expected {
+		if !paths[i].Equal(expected[i]) {
+			t.Fatalf("expected path %d to be %s, got %s", i, expected[i], paths[i])
+		}
+	}
+
+	if _, err := FieldPathToTftypesPaths("status.ingress[*]hostname"); err == nil {
+		t.Fatal("expected an error for a splat followed by a name")
+	}
+	if _, err := FieldPathToTftypesPath("status.ingress[*].hostname"); err == nil {
+		t.Fatal("expected FieldPathToTftypesPath to reject splats")
+	}
+}
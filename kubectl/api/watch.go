@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// ObjectCheck decides from a single read of an object whether a wait is done.
+// An error ends the wait.
+type ObjectCheck func(obj *unstructured.Unstructured) (bool, error)
+
+// WaitForObject runs check against the named object in rs until it reports
+// done or fails. The object is read once, then watched from the resource
+// version of that read, so that check runs on every change of the object
+// without polling the API server. A watch that ends is re-established from
+// the last resource version seen, and the object is read again when that
+// version has expired. Where watching is forbidden, the object is polled
+// every WaiterSleepTime instead.
+//
+// When ctx ends first, a WaiterError for reason is returned.
+func WaitForObject(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	name string,
+	reason string,
+	check ObjectCheck,
+) error {
+	resourceVersion := ""
+	for {
+		if resourceVersion == "" {
+			res, err := rs.Get(ctx, name, v1.GetOptions{})
+			if err != nil {
+				return waitError(ctx, reason, err)
+			}
+			if done, err := check(res); done || err != nil {
+				return err
+			}
+			resourceVersion = res.GetResourceVersion()
+		}
+
+		w, err := rs.Watch(ctx, v1.ListOptions{
+			FieldSelector:       fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if errors.IsForbidden(err) || errors.IsMethodNotSupported(err) {
+			return pollObject(ctx, rs, name, reason, check)
+		}
+		if err != nil {
+			return waitError(ctx, reason, err)
+		}
+		var done bool
+		resourceVersion, done, err = watchObject(ctx, w, name, resourceVersion, check)
+		if done || err != nil {
+			return err
+		}
+		if err := sleep(ctx); err != nil {
+			return WaiterError{Reason: reason}
+		}
+	}
+}
+
+// watchObject runs check on the events of w about the named object until
+// the check is done or fails, or the watch or ctx ends. It returns the
+// resource version to resume watching from, "" when it has expired, and
+// whether the check is done.
+func watchObject(
+	ctx context.Context,
+	w watch.Interface,
+	name string,
+	resourceVersion string,
+	check ObjectCheck,
+) (string, bool, error) {
+	defer w.Stop()
+	for {
+		var event watch.Event
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return resourceVersion, false, nil
+		case event, ok = <-w.ResultChan():
+			if !ok {
+				return resourceVersion, false, nil
+			}
+		}
+		if event.Type == watch.Error {
+			err := errors.FromObject(event.Object)
+			if errors.IsResourceExpired(err) || errors.IsGone(err) {
+				return "", false, nil
+			}
+			return resourceVersion, false, fmt.Errorf("failed to watch resource: %w", err)
+		}
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		resourceVersion = obj.GetResourceVersion()
+		if event.Type == watch.Bookmark || obj.GetName() != name {
+			continue
+		}
+		if event.Type == watch.Deleted {
+			return resourceVersion, false, fmt.Errorf("resource was deleted")
+		}
+		if done, err := check(obj); done || err != nil {
+			return resourceVersion, done, err
+		}
+	}
+}
+
+// pollObject runs check against the named object in rs every
+// WaiterSleepTime until it reports done or fails.
+func pollObject(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	name string,
+	reason string,
+	check ObjectCheck,
+) error {
+	for {
+		res, err := rs.Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return waitError(ctx, reason, err)
+		}
+		if done, err := check(res); done || err != nil {
+			return err
+		}
+		if err := sleep(ctx); err != nil {
+			return WaiterError{Reason: reason}
+		}
+	}
+}
+
+// waitError returns err, or a WaiterError for reason if ctx has ended.
+func waitError(ctx context.Context, reason string, err error) error {
+	if ctx.Err() != nil {
+		return WaiterError{Reason: reason}
+	}
+	return err
+}
+
+// sleep waits for WaiterSleepTime, or until ctx ends.
+func sleep(ctx context.Context) error {
+	t := time.NewTimer(WaiterSleepTime)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
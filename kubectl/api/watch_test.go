@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func watchTestJob(phase string) *unstructured.Unstructured {
+	uo := &unstructured.Unstructured{}
+	uo.SetAPIVersion("batch/v1")
+	uo.SetKind("Job")
+	uo.SetNamespace("default")
+	uo.SetName("migrate")
+	_ = unstructured.SetNestedField(uo.Object, phase, "status", "phase")
+	return uo
+}
+
+func phaseIs(phase string) ObjectCheck {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		p, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return p == phase, nil
+	}
+}
+
+func TestWaitForObject(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	expired := &v1.Status{Status: v1.StatusFailure, Code: 410, Reason: v1.StatusReasonExpired}
+
+	samples := map[string]struct {
+		initial  string
+		events   []watch.Event
+		gets     int
+		expected string
+	}{
+		"done on read": {
+			initial: "Complete",
+			gets:    1,
+		},
+		"done on event": {
+			initial: "Running",
+			events: []watch.Event{
+				{Type: watch.Modified, Object: watchTestJob("Running")},
+				{Type: watch.Modified, Object: watchTestJob("Complete")},
+			},
+			gets: 1,
+		},
+		"deleted": {
+			initial:  "Running",
+			events:   []watch.Event{{Type: watch.Deleted, Object: watchTestJob("Running")}},
+			gets:     1,
+			expected: "resource was deleted",
+		},
+		"expired resource version": {
+			initial: "Running",
+			events:  []watch.Event{{Type: watch.Error, Object: expired}},
+			gets:    2,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+				watchTestJob(s.initial))
+			gets := 0
+			client.PrependReactor("get", "jobs",
+				func(a k8stesting.Action) (bool, runtime.Object, error) {
+					gets++
+					if gets > 1 {
+						return true, watchTestJob("Complete"), nil
+					}
+					return false, nil, nil
+				})
+			client.PrependWatchReactor("jobs",
+				func(a k8stesting.Action) (bool, watch.Interface, error) {
+					w := watch.NewFake()
+					go func() {
+						for _, e := range s.events {
+							w.Action(e.Type, e.Object)
+						}
+						w.Stop()
+					}()
+					return true, w, nil
+				})
+			rs := client.Resource(gvr).Namespace("default")
+
+			err := WaitForObject(context.Background(), rs, "migrate", "job", phaseIs("Complete"))
+			if s.expected == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.expected != "" && (err == nil || err.Error() != s.expected) {
+				t.Fatalf("expected error %q, got %v", s.expected, err)
+			}
+			if gets != s.gets {
+				t.Fatalf("expected %d reads, got %d", s.gets, gets)
+			}
+		})
+	}
+}
+
+func TestWaitForObjectWatchForbidden(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), watchTestJob("Running"))
+	gets := 0
+	client.PrependReactor("get", "jobs",
+		func(a k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			if gets > 1 {
+				return true, watchTestJob("Complete"), nil
+			}
+			return false, nil, nil
+		})
+	client.PrependWatchReactor("jobs",
+		func(a k8stesting.Action) (bool, watch.Interface, error) {
+			err := errors.New("no watch")
+			return true, nil, k8s_errors.NewForbidden(gvr.GroupResource(), "", err)
+		})
+	rs := client.Resource(gvr).Namespace("default")
+
+	if err := WaitForObject(context.Background(), rs, "migrate", "job",
+		phaseIs("Complete")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gets != 2 {
+		t.Fatalf("expected the object to be polled, got %d reads", gets)
+	}
+}
+
+func TestWaitForObjectTimeout(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), watchTestJob("Running"))
+	client.PrependWatchReactor("jobs",
+		func(a k8stesting.Action) (bool, watch.Interface, error) {
+			return true, watch.NewFake(), nil
+		})
+	rs := client.Resource(gvr).Namespace("default")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WaitForObject(ctx, rs, "migrate", "job", phaseIs("Complete"))
+	var we WaiterError
+	if !errors.As(err, &we) || we.Reason != "job" {
+		t.Fatalf("expected a WaiterError, got %v", err)
+	}
+}
@@ -5,18 +5,32 @@
 
 import "sync"
 
-// cache is a generic type that lazily initializes a value using sync.Once.
+// cache is a generic type that lazily initializes a value on first use.
 // It is used to cache expensive-to-create clients and other resources.
+// Unlike sync.Once, it can be reset so that the value is rebuilt on the
+// next call, e.g. when a new CRD changes the cluster's schema.
 type cache[T any] struct {
-	once  sync.Once
+	mu    sync.Mutex
+	done  bool
 	value T
 	err   error
 }
 
 // Get returns the cached value, initializing it on first call using the provided function.
 func (c *cache[T]) Get(f func() (T, error)) (T, error) {
-	c.once.Do(func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.done {
 		c.value, c.err = f()
-	})
+		c.done = true
+	}
 	return c.value, c.err
 }
+
+// Reset discards the cached value so that the next call to Get re-initializes it.
+func (c *cache[T]) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var zero T
+	c.value, c.err, c.done = zero, nil, false
+}
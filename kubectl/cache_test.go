@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCacheReset(t *testing.T) {
+	var c cache[int]
+	calls := 0
+	get := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for range 2 {
+		if v, _ := c.Get(get); v != 1 {
+			t.Fatalf("expected cached value 1, got %d", v)
+		}
+	}
+
+	c.Reset()
+	if v, _ := c.Get(get); v != 2 {
+		t.Fatalf("expected value to be rebuilt after reset, got %d", v)
+	}
+
+	c.Reset()
+	_, err := c.Get(func() (int, error) { return 0, errors.New("boom") })
+	if err == nil {
+		t.Fatal("expected error to be cached until reset")
+	}
+	if _, err := c.Get(get); err == nil {
+		t.Fatal("expected cached error to be returned")
+	}
+}
@@ -5,12 +5,21 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/util"
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -30,29 +39,113 @@ func (p *kubectlProviderData) getDynamicClient() (dynamic.Interface, error) {
 }
 
 // getDiscoveryClient returns a configured discovery client instance.
+// When cache_dir is set, discovery documents are persisted on disk and
+// revalidated using the HTTP cache once they are older than cache_ttl.
 func (p *kubectlProviderData) getDiscoveryClient() (discovery.DiscoveryInterface, error) {
 	return p.discoveryClient.Get(func() (discovery.DiscoveryInterface, error) {
 		cfg, err := p.getRestConfig()
 		if err != nil {
 			return nil, fmt.Errorf("cannot create discovery client: %w", err)
 		}
-		return discovery.NewDiscoveryClientForConfig(cfg)
+		dc := p.getDiskCache()
+		if dc == nil {
+			return discovery.NewDiscoveryClientForConfig(cfg)
+		}
+		return disk.NewCachedDiscoveryClientForConfig(
+			cfg,
+			filepath.Join(dc.dir, "discovery"),
+			filepath.Join(p.cacheDir, "http"),
+			p.cacheTTL,
+		)
+	})
+}
+
+// getCachedDiscoveryClient returns the discovery client shared by the
+// RESTMapper and any kubectl helpers that need a CachedDiscoveryInterface.
+func (p *kubectlProviderData) getCachedDiscoveryClient() (
+	discovery.CachedDiscoveryInterface,
+	error,
+) {
+	return p.cachedDiscoveryClient.Get(func() (discovery.CachedDiscoveryInterface, error) {
+		dc, err := p.getDiscoveryClient()
+		if err != nil {
+			return nil, err
+		}
+		if cdc, ok := dc.(discovery.CachedDiscoveryInterface); ok {
+			return cdc, nil
+		}
+		return memory.NewMemCacheClient(dc), nil
 	})
 }
 
 // getRestMapper returns a RESTMapper client instance using in-memory cache.
+// The mapper is shared by all operations; it re-runs discovery only when
+// Reset is called after a lookup misses.
 func (p *kubectlProviderData) getRestMapper() (meta.RESTMapper, error) {
 	return p.restMapper.Get(func() (meta.RESTMapper, error) {
-		dc, err := p.getDiscoveryClient()
+		cacheClient, err := p.getCachedDiscoveryClient()
 		if err != nil {
 			return nil, err
 		}
-
-		cacheClient := memory.NewMemCacheClient(dc)
 		return restmapper.NewDeferredDiscoveryRESTMapper(cacheClient), nil
 	})
 }
 
+// getRestClientFromUnstructured returns a dynamic resource client for the
+// manifest, resolved through the shared RESTMapper and dynamic client.
+func (p *kubectlProviderData) getRestClientFromUnstructured(
+	ctx context.Context,
+	manifest *yaml.Manifest,
+) *api.RestClientResult {
+	rm, err := p.getRestMapper()
+	if err != nil {
+		return api.RestClientResultFromErr(fmt.Errorf("failed to get REST mapper: %w", err))
+	}
+	client, err := p.getDynamicClient()
+	if err != nil {
+		return api.RestClientResultFromErr(fmt.Errorf("failed to get dynamic client: %w", err))
+	}
+	return api.GetRestClientFromUnstructured(ctx, manifest, rm, client)
+}
+
+// getDiskCache returns the on-disk cache for the configured cluster, or nil
+// when cache_dir is not set. Entries are keyed by host and server version so
+// that an upgraded cluster never reuses schemas from the previous version.
+// When the server version cannot be determined it also returns nil, so the
+// caller goes to the API server directly, and tries again on the next call.
+func (p *kubectlProviderData) getDiskCache() *diskCache {
+	dc, err := p.diskCache.Get(func() (*diskCache, error) {
+		if p.cacheDir == "" {
+			return nil, nil
+		}
+		cfg, err := p.getRestConfig()
+		if err != nil {
+			return nil, err
+		}
+		dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		v, err := dc.ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine server version for cache: %w", err)
+		}
+		return &diskCache{
+			dir: filepath.Join(
+				util.ComputeDiscoverCacheDir(p.cacheDir, cfg.Host),
+				sanitizeCacheKey(v.GitVersion),
+			),
+			ttl:    p.cacheTTL,
+			logger: p.logger,
+		}, nil
+	})
+	if err != nil {
+		p.diskCache.Reset()
+		p.logger.Warn("not using the on-disk cache", "error", err)
+	}
+	return dc
+}
+
 // getRestClient returns a raw REST client instance.
 func (p *kubectlProviderData) getRestClient() (rest.Interface, error) {
 	return p.restClient.Get(func() (rest.Interface, error) {
@@ -69,22 +162,145 @@ func (p *kubectlProviderData) getRestClient() (rest.Interface, error) {
 // getOAPIv2Foundry returns an interface to request tftype types from an OpenAPIv2 spec.
 func (p *kubectlProviderData) getOAPIv2Foundry() (api.Foundry, error) {
 	return p.OAPIFoundry.Get(func() (api.Foundry, error) {
-		rc, err := p.getRestClient()
+		rs, err := p.fetchOpenAPIv2()
 		if err != nil {
 			return nil, fmt.Errorf("failed get OpenAPI spec: %s", err)
 		}
 
-		rq := rc.Verb("GET").Timeout(30*time.Second).AbsPath("openapi", "v2")
-		rs, err := rq.DoRaw(context.TODO())
+		oapif, err := api.NewFoundryFromSpecV2(rs)
 		if err != nil {
-			return nil, fmt.Errorf("failed get OpenAPI spec: %s", err)
+			return nil, fmt.Errorf("failed construct OpenAPI foundry: %s", err)
 		}
 
-		oapif, err := api.NewFoundryFromSpecV2(rs)
+		return oapif, nil
+	})
+}
+
+// fetchOpenAPIv2 downloads the OpenAPI v2 document, going through the
+// on-disk cache when one is configured.
+func (p *kubectlProviderData) fetchOpenAPIv2() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	if dc := p.getDiskCache(); dc != nil {
+		cfg, err := p.getRestConfig()
 		if err != nil {
-			return nil, fmt.Errorf("failed construct OpenAPI foundry: %s", err)
+			return nil, err
+		}
+		return fetchCached(ctx, cfg, dc, "openapi/v2", "openapi", "v2")
+	}
+
+	rc, err := p.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+	return rc.Verb("GET").AbsPath("openapi", "v2").DoRaw(ctx)
+}
+
+// getOAPIv3Foundry returns a foundry for the given group version, built from
+// the per-group-version OpenAPI v3 document. The document is only fetched the
+// first time a type from that group version is requested. It returns a nil
+// foundry without error when the API server does not serve OpenAPI v3, so
+// callers can fall back to the v2 foundry. Other errors, e.g. from an
+// aggregated API that is temporarily unavailable, are not cached.
+func (p *kubectlProviderData) getOAPIv3Foundry(gv schema.GroupVersion) (api.Foundry, error) {
+	c, _ := p.OAPIv3Foundries.LoadOrStore(gv, &cache[api.Foundry]{})
+	fc := c.(*cache[api.Foundry]) //nolint:forcetypeassert
+	f, err := fc.Get(func() (api.Foundry, error) {
+		rs, err := p.fetchOpenAPIv3(gv)
+		if errors.Is(err, errDocumentNotFound) || apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed get OpenAPI v3 spec for %s: %s", gv, err)
+		}
+
+		oapif, err := api.NewFoundryFromSpecV3(rs)
+		if err != nil {
+			return nil, fmt.Errorf("failed construct OpenAPI v3 foundry for %s: %s", gv, err)
 		}
 
 		return oapif, nil
 	})
+	if err != nil {
+		fc.Reset()
+	}
+	return f, err
+}
+
+// fetchOpenAPIv3 downloads the OpenAPI v3 document for a group version,
+// going through the on-disk cache when one is configured.
+func (p *kubectlProviderData) fetchOpenAPIv3(gv schema.GroupVersion) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	absPath := openAPIv3Path(gv)
+
+	if dc := p.getDiskCache(); dc != nil {
+		cfg, err := p.getRestConfig()
+		if err != nil {
+			return nil, err
+		}
+		return fetchCached(ctx, cfg, dc, path.Join(absPath...), absPath...)
+	}
+
+	rc, err := p.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+	return rc.Verb("GET").AbsPath(absPath...).DoRaw(ctx)
+}
+
+// openAPIv3Path returns the path segments of the OpenAPI v3 document for gv.
+func openAPIv3Path(gv schema.GroupVersion) []string {
+	if gv.Group == "" {
+		return []string{"openapi", "v3", "api", gv.Version}
+	}
+	return []string{"openapi", "v3", "apis", gv.Group, gv.Version}
+}
+
+// invalidateSchemaCaches drops everything derived from the set of CRDs in the
+// cluster after crd has been created or changed: its cached schemas, the
+// RESTMapper and the OpenAPI foundries, in memory and on disk. They are
+// rebuilt lazily the next time they are needed.
+func (p *kubectlProviderData) invalidateSchemaCaches(crd *unstructured.Unstructured) {
+	dc := p.getDiskCache()
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	gk := schema.GroupKind{Group: group, Kind: kind}
+	p.crdSchemas.Range(func(k, _ any) bool {
+		if gvk, ok := k.(schema.GroupVersionKind); ok && gvk.GroupKind() == gk {
+			p.crdSchemas.Delete(k)
+		}
+		return true
+	})
+	p.crdFoundries.Range(func(k, _ any) bool {
+		if key, ok := k.(crdFoundryKey); ok && key.gvk.GroupKind() == gk {
+			p.crdFoundries.Delete(k)
+		}
+		return true
+	})
+	dc.invalidate(crdCacheKeyPrefix + crd.GetName())
+
+	if rm, err := p.getRestMapper(); err == nil {
+		if rrm, ok := rm.(meta.ResettableRESTMapper); ok {
+			rrm.Reset()
+		}
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		vm, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := vm["name"].(string)
+		gv := schema.GroupVersion{Group: group, Version: name}
+		p.OAPIv3Foundries.Delete(gv)
+		dc.invalidate(path.Join(openAPIv3Path(gv)...))
+	}
+
+	p.OAPIFoundry.Reset()
+	dc.invalidate("openapi/v2")
 }
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/go-hclog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestInvalidateSchemaCaches(t *testing.T) {
+	var widgetCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi/v3/apis/example.com/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		widgetCalls.Add(1)
+		_, _ = w.Write([]byte(widgetSpecV3))
+	}))
+	defer srv.Close()
+
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	_, _ = p.restConfig.Get(func() (*rest.Config, error) {
+		return &rest.Config{Host: srv.URL}, nil
+	})
+
+	widgetGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	gadgetGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}
+	p.crdSchemas.Store(widgetGVK, &cache[any]{})
+	p.crdSchemas.Store(gadgetGVK, &cache[any]{})
+	widgets := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	apps := schema.GroupVersion{Group: "apps", Version: "v1"}
+	appsFoundry := &cache[api.Foundry]{}
+	p.OAPIv3Foundries.Store(apps, appsFoundry)
+	if _, err := p.getOAPIv3Foundry(widgets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "Widget"},
+			"versions": []any{
+				map[string]any{"name": "v1"},
+			},
+		},
+	}}
+	p.invalidateSchemaCaches(crd)
+
+	if _, ok := p.crdSchemas.Load(widgetGVK); ok {
+		t.Fatal("expected schema of the applied CRD to be dropped")
+	}
+	if _, ok := p.crdSchemas.Load(gadgetGVK); !ok {
+		t.Fatal("expected schema of an unrelated CRD to be kept")
+	}
+	if c, ok := p.OAPIv3Foundries.Load(apps); !ok || c != appsFoundry {
+		t.Fatal("expected unrelated foundry to be kept")
+	}
+
+	f, err := p.getOAPIv3Foundry(widgets)
+	if err != nil || f == nil {
+		t.Fatalf("expected a foundry for the CRD's group version, got %v, %v", f, err)
+	}
+	if n := widgetCalls.Load(); n != 2 {
+		t.Fatalf("expected the document to be refetched after invalidation, got %d requests", n)
+	}
+}
+
+func TestGetOAPIv3FoundryErrors(t *testing.T) {
+	var widgetCalls, missingCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi/v3/apis/example.com/v1":
+			if widgetCalls.Add(1) == 1 {
+				http.Error(w, "aggregated API unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(widgetSpecV3))
+		default:
+			missingCalls.Add(1)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	_, _ = p.restConfig.Get(func() (*rest.Config, error) {
+		return &rest.Config{Host: srv.URL}, nil
+	})
+
+	widgets := schema.GroupVersion{Group: "example.com", Version: "v1"}
+	if _, err := p.getOAPIv3Foundry(widgets); err == nil {
+		t.Fatal("expected an error while the API is unavailable")
+	}
+	f, err := p.getOAPIv3Foundry(widgets)
+	if err != nil || f == nil {
+		t.Fatalf("expected the document to be refetched after an error, got %v, %v", f, err)
+	}
+	if n := widgetCalls.Load(); n != 2 {
+		t.Fatalf("expected 2 requests, got %d", n)
+	}
+
+	missing := schema.GroupVersion{Group: "other.example.com", Version: "v1"}
+	for range 2 {
+		if f, err := p.getOAPIv3Foundry(missing); f != nil || err != nil {
+			t.Fatalf("expected no foundry and no error for a missing document, got %v, %v", f, err)
+		}
+	}
+	if n := missingCalls.Load(); n != 1 {
+		t.Fatalf("expected a missing document to be cached, got %d requests", n)
+	}
+}
+
+const widgetSpecV3 = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1"},
+  "paths": {},
+  "components": {"schemas": {"io.example.Widget": {
+    "type": "object",
+    "x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Widget"}],
+    "properties": {"spec": {"type": "object"}}
+  }}}
+}`
+
+func TestGetDiskCacheRetriesServerVersion(t *testing.T) {
+	var up atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" || !up.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"gitVersion": "v1.33.1"}`))
+	}))
+	defer srv.Close()
+
+	p := &kubectlProviderData{cacheDir: t.TempDir(), logger: hclog.NewNullLogger()}
+	_, _ = p.restConfig.Get(func() (*rest.Config, error) {
+		return &rest.Config{Host: srv.URL}, nil
+	})
+
+	if dc := p.getDiskCache(); dc != nil {
+		t.Fatalf("expected no disk cache while the server version is unknown, got %v", dc)
+	}
+	up.Store(true)
+	if dc := p.getDiskCache(); dc == nil {
+		t.Fatal("expected the disk cache once the server version is known")
+	}
+}
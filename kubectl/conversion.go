@@ -164,12 +164,18 @@ func encodeMap(mv basetypes.MapValue) (map[string]any, error) {
 	return m, nil
 }
 
+// unknownValue stands for a value that is not known until apply in the maps
+// passed to mapToDynamic, which decodes it as an unknown value.
+type unknownValue struct{}
+
 // decodeAny converts any to attr.Value
 // Based on kubectl/functions/decode.go:decodeScalar.
 func decodeAny(ctx context.Context, m any) (value attr.Value, diags diag.Diagnostics) {
 	switch v := m.(type) {
 	case nil:
 		value = types.DynamicNull()
+	case unknownValue:
+		value = types.DynamicUnknown()
 	case float64:
 		value = types.NumberValue(big.NewFloat(float64(v)))
 	case int:
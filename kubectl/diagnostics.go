@@ -4,34 +4,239 @@
 package kubectl
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // APIStatusErrorToDiagnostics converts a Kubernetes API machinery StatusError into Terraform Diagnostics.
-func APIStatusErrorToDiagnostics(s metav1.Status) diag.Diagnostics {
+// Each cause naming a field is reported against the matching element of the manifest attribute,
+// resolved against manifest; causes of server-side apply conflicts name the conflicting field
+// manager and how to resolve the conflict.
+func APIStatusErrorToDiagnostics(s metav1.Status, manifest map[string]any) diag.Diagnostics {
 	var diags diag.Diagnostics
-	diags.AddError(
-		"API response status: "+s.Status,
-		s.Message,
-	)
-	if s.Details == nil {
+	if s.Details == nil || len(s.Details.Causes) == 0 {
+		diags.AddError(
+			"API response status: "+s.Status,
+			s.Message,
+		)
 		return diags
 	}
-	gk := metav1.GroupKind{Group: s.Details.Group, Kind: s.Details.Kind}
-	diags.AddError(
-		fmt.Sprintf(
-			"Kubernetes API Error: %s %s [%s]",
-			string(s.Reason),
-			gk.String(),
-			s.Details.Name,
-		),
-		"",
-	)
 	for _, c := range s.Details.Causes {
-		diags.AddError(c.Field, c.Message)
+		summary, detail := statusCauseMessage(s, c)
+		if c.Field == "" {
+			diags.AddError(summary, detail)
+			continue
+		}
+		diags.AddAttributeError(manifestFieldPath(manifest, c.Field), summary, detail)
 	}
 	return diags
 }
+
+// applyErrorDiagnostics returns field-level diagnostics for err if it is an
+// API status error with causes, or nil.
+func applyErrorDiagnostics(err error, manifest map[string]any) diag.Diagnostics {
+	var status k8s_errors.APIStatus
+	if !errors.As(err, &status) {
+		return nil
+	}
+	s := status.Status()
+	if s.Details == nil || len(s.Details.Causes) == 0 {
+		return nil
+	}
+	return APIStatusErrorToDiagnostics(s, manifest)
+}
+
+// isPermanentApplyError reports whether an apply failed in a way retrying
+// cannot fix: the object is invalid or conflicts with another field manager.
+func isPermanentApplyError(err error) bool {
+	if k8s_errors.IsInvalid(err) {
+		return true
+	}
+	return k8s_errors.IsConflict(err) &&
+		k8s_errors.HasStatusCause(err, metav1.CauseTypeFieldManagerConflict)
+}
+
+// immutableFieldMessage matches the messages of API validation errors that
+// reject a change to a field that cannot change after creation, such as
+// "field is immutable" for Job spec.template and Service clusterIP, and the
+// "updates to statefulset spec for fields other than ... are forbidden" of
+// StatefulSets.
+var immutableFieldMessage = regexp.MustCompile(`(?i)\bimmutable\b|updates to .* are forbidden`)
+
+// isImmutableFieldError reports whether err is a validation error rejecting a
+// change to an immutable field.
+func isImmutableFieldError(err error) bool {
+	var status k8s_errors.APIStatus
+	if !errors.As(err, &status) || !k8s_errors.IsInvalid(err) {
+		return false
+	}
+	s := status.Status()
+	if s.Details == nil || len(s.Details.Causes) == 0 {
+		return immutableFieldMessage.MatchString(s.Message)
+	}
+	for _, c := range s.Details.Causes {
+		if immutableFieldMessage.MatchString(c.Message) {
+			return true
+		}
+	}
+	return false
+}
+
+var conflictManager = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// statusCauseMessage returns the summary and detail of a diagnostic for c.
+func statusCauseMessage(s metav1.Status, c metav1.StatusCause) (string, string) {
+	field := strings.TrimPrefix(c.Field, ".")
+	if c.Type != metav1.CauseTypeFieldManagerConflict {
+		detail := c.Message
+		if field != "" {
+			detail = fmt.Sprintf("%s: %s", field, c.Message)
+		}
+		return fmt.Sprintf("Kubernetes API Error: %s", s.Reason), detail
+	}
+
+	detail := fmt.Sprintf("%s: %s.", field, c.Message)
+	manager := "the other field manager"
+	if m := conflictManager.FindStringSubmatch(c.Message); m != nil {
+		manager = strconv.Quote(m[1])
+		detail = fmt.Sprintf("The value of %s conflicts with field manager %s, which owns it.",
+			field, manager)
+	}
+	detail += "\n\nSet field_manager.force_conflicts to take ownership of the field"
+	if !strings.ContainsAny(field, "[]") {
+		detail += fmt.Sprintf(", or add %q to fields.computed to leave it to %s", field, manager)
+	}
+	return "Field Manager Conflict", detail + "."
+}
+
+// manifestFieldPath resolves a field path reported by the API server against
+// manifest. Both the validation form, e.g. spec.containers[0].image or
+// metadata.labels[app], and the server-side apply form, e.g.
+// .spec.containers[name="app"].image, are understood. The returned path
+// points at the deepest element of the manifest attribute that exists.
+func manifestFieldPath(manifest map[string]any, field string) path.Path {
+	p := path.Root("manifest")
+	var cur any = manifest
+	rest := strings.TrimPrefix(field, ".")
+	for rest != "" {
+		switch node := cur.(type) {
+		case map[string]any:
+			key, n := matchMapKey(node, rest)
+			if n == 0 {
+				return p
+			}
+			p = p.AtName(key)
+			cur = node[key]
+			rest = rest[n:]
+		case []any:
+			end := strings.IndexByte(rest, ']')
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				return p
+			}
+			i := selectListItem(node, rest[1:end])
+			if i < 0 {
+				return p
+			}
+			p = p.AtListIndex(i)
+			cur = node[i]
+			rest = rest[end+1:]
+		default:
+			return p
+		}
+		rest = strings.TrimPrefix(rest, ".")
+	}
+	return p
+}
+
+// matchMapKey returns the key of m that rest starts with, either in brackets
+// or as the longest key followed by a separator, since keys such as label
+// names may contain dots. It returns the number of bytes consumed, or 0.
+func matchMapKey(m map[string]any, rest string) (string, int) {
+	if strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return "", 0
+		}
+		if _, ok := m[rest[1:end]]; ok {
+			return rest[1:end], end + 1
+		}
+		return "", 0
+	}
+	best := ""
+	for k := range m {
+		if len(k) <= len(best) || !strings.HasPrefix(rest, k) {
+			continue
+		}
+		if len(rest) == len(k) || rest[len(k)] == '.' || rest[len(k)] == '[' {
+			best = k
+		}
+	}
+	return best, len(best)
+}
+
+// selectListItem returns the index of the item of list selected by sel, the
+// content of a path step in brackets: an index, a set value such as ="a",
+// or key fields such as name="app",protocol="TCP". It returns -1 if no item
+// matches.
+func selectListItem(list []any, sel string) int {
+	if i, err := strconv.Atoi(sel); err == nil {
+		if i >= 0 && i < len(list) {
+			return i
+		}
+		return -1
+	}
+	if v, ok := strings.CutPrefix(sel, "="); ok {
+		var want any
+		if err := json.Unmarshal([]byte(v), &want); err != nil {
+			return -1
+		}
+		for i, item := range list {
+			if reflect.DeepEqual(item, want) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	keys := map[string]any{}
+	for sel != "" {
+		name, v, ok := strings.Cut(sel, "=")
+		if !ok {
+			return -1
+		}
+		dec := json.NewDecoder(strings.NewReader(v))
+		var val any
+		if err := dec.Decode(&val); err != nil {
+			return -1
+		}
+		keys[name] = val
+		sel = strings.TrimPrefix(v[dec.InputOffset():], ",")
+	}
+	for i, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		match := true
+		for k, v := range keys {
+			if !reflect.DeepEqual(m[k], v) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,365 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var diagnosticsTestManifest = map[string]any{
+	"metadata": map[string]any{
+		"name": "web",
+		"labels": map[string]any{
+			"app":                    "web",
+			"app.kubernetes.io/name": "web",
+		},
+	},
+	"spec": map[string]any{
+		"replicas": float64(2),
+		"finalizers": []any{
+			"a", "b",
+		},
+		"containers": []any{
+			map[string]any{"name": "sidecar", "image": "envoy"},
+			map[string]any{
+				"name":  "app",
+				"image": "nginx",
+				"ports": []any{
+					map[string]any{"containerPort": float64(80), "protocol": "TCP"},
+					map[string]any{"containerPort": float64(80), "protocol": "UDP"},
+				},
+			},
+		},
+	},
+}
+
+func TestManifestFieldPath(t *testing.T) {
+	m := path.Root("manifest")
+	samples := map[string]struct {
+		field    string
+		expected path.Path
+	}{
+		"validation form": {
+			field:    "spec.containers[1].image",
+			expected: m.AtName("spec").AtName("containers").AtListIndex(1).AtName("image"),
+		},
+		"bracketed map key": {
+			field:    "metadata.labels[app]",
+			expected: m.AtName("metadata").AtName("labels").AtName("app"),
+		},
+		"apply form with leading dot": {
+			field:    ".spec.replicas",
+			expected: m.AtName("spec").AtName("replicas"),
+		},
+		"keyed list item": {
+			field:    `.spec.containers[name="app"].image`,
+			expected: m.AtName("spec").AtName("containers").AtListIndex(1).AtName("image"),
+		},
+		"multiple keys": {
+			field: `.spec.containers[name="app"].ports[containerPort=80,protocol="UDP"]`,
+			expected: m.AtName("spec").AtName("containers").AtListIndex(1).
+				AtName("ports").AtListIndex(1),
+		},
+		"set value": {
+			field:    `.spec.finalizers[="b"]`,
+			expected: m.AtName("spec").AtName("finalizers").AtListIndex(1),
+		},
+		"key containing dots": {
+			field:    ".metadata.labels.app.kubernetes.io/name",
+			expected: m.AtName("metadata").AtName("labels").AtName("app.kubernetes.io/name"),
+		},
+		"missing field stops at deepest existing element": {
+			field:    "spec.template.spec",
+			expected: m.AtName("spec"),
+		},
+		"unmatched key": {
+			field:    `.spec.containers[name="db"].image`,
+			expected: m.AtName("spec").AtName("containers"),
+		},
+		"index out of range": {
+			field:    "spec.containers[5]",
+			expected: m.AtName("spec").AtName("containers"),
+		},
+		"empty": {
+			field:    "",
+			expected: m,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			got := manifestFieldPath(diagnosticsTestManifest, s.field)
+			if !got.Equal(s.expected) {
+				t.Fatalf("expected %s, got %s", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchMapKey(t *testing.T) {
+	m := map[string]any{"app": 1, "app.kubernetes.io": 2, "tier": 3}
+	samples := []struct {
+		rest string
+		key  string
+		n    int
+	}{
+		{"app", "app", 3},
+		{"app.x", "app", 3},
+		{"app.kubernetes.io.y", "app.kubernetes.io", 17},
+		{"tier[0]", "tier", 4},
+		{"[tier].x", "tier", 6},
+		{"tiers", "", 0},
+		{"[missing]", "", 0},
+		{"[unterminated", "", 0},
+	}
+	for _, s := range samples {
+		key, n := matchMapKey(m, s.rest)
+		if key != s.key || n != s.n {
+			t.Errorf("%q: expected (%q, %d), got (%q, %d)", s.rest, s.key, s.n, key, n)
+		}
+	}
+}
+
+func TestSelectListItem(t *testing.T) {
+	list := []any{
+		map[string]any{"name": "a", "port": float64(80)},
+		map[string]any{"name": "b", "port": float64(80)},
+		"plain",
+	}
+	samples := []struct {
+		sel      string
+		expected int
+	}{
+		{"0", 0},
+		{"2", 2},
+		{"3", -1},
+		{"-1", -1},
+		{`name="b"`, 1},
+		{`port=80,name="b"`, 1},
+		{`port=81`, -1},
+		{`="plain"`, 2},
+		{`="other"`, -1},
+		{`name=`, -1},
+		{`garbage`, -1},
+	}
+	for _, s := range samples {
+		if got := selectListItem(list, s.sel); got != s.expected {
+			t.Errorf("%q: expected %d, got %d", s.sel, s.expected, got)
+		}
+	}
+}
+
+func TestStatusCauseMessage(t *testing.T) {
+	samples := map[string]struct {
+		status   metav1.Status
+		cause    metav1.StatusCause
+		summary  string
+		contains []string
+		excludes []string
+	}{
+		"field manager conflict": {
+			status: metav1.Status{Reason: metav1.StatusReasonConflict},
+			cause: metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: `conflict with "kubectl-edit" using apps/v1`,
+				Field:   ".spec.replicas",
+			},
+			summary: "Field Manager Conflict",
+			contains: []string{
+				`spec.replicas conflicts with field manager "kubectl-edit"`,
+				"field_manager.force_conflicts",
+				`add "spec.replicas" to fields.computed`,
+			},
+		},
+		"conflict in list item": {
+			status: metav1.Status{Reason: metav1.StatusReasonConflict},
+			cause: metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: `conflict with "istio" using v1`,
+				Field:   `.spec.containers[name="app"].image`,
+			},
+			summary:  "Field Manager Conflict",
+			contains: []string{`"istio"`, "field_manager.force_conflicts"},
+			excludes: []string{"fields.computed"},
+		},
+		"conflict without manager": {
+			status: metav1.Status{Reason: metav1.StatusReasonConflict},
+			cause: metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: "conflicting ownership",
+				Field:   ".data.key",
+			},
+			summary: "Field Manager Conflict",
+			contains: []string{
+				"data.key: conflicting ownership.",
+				"leave it to the other field manager",
+			},
+		},
+		"invalid value": {
+			status: metav1.Status{Reason: metav1.StatusReasonInvalid},
+			cause: metav1.StatusCause{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Message: "Invalid value: -1: must be greater than or equal to 0",
+				Field:   "spec.replicas",
+			},
+			summary:  "Kubernetes API Error: Invalid",
+			contains: []string{"spec.replicas: Invalid value: -1"},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			summary, detail := statusCauseMessage(s.status, s.cause)
+			if summary != s.summary {
+				t.Fatalf("unexpected summary %q", summary)
+			}
+			for _, c := range s.contains {
+				if !strings.Contains(detail, c) {
+					t.Errorf("expected %q in detail %q", c, detail)
+				}
+			}
+			for _, c := range s.excludes {
+				if strings.Contains(detail, c) {
+					t.Errorf("unexpected %q in detail %q", c, detail)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyErrorDiagnostics(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	invalid := k8s_errors.NewInvalid(gk, "web", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "replicas"), -1, "must be non-negative"),
+	})
+	err := fmt.Errorf("failed to apply manifest: %w", invalid)
+
+	diags := applyErrorDiagnostics(err, diagnosticsTestManifest)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags)
+	}
+	withPath, ok := diags[0].(interface{ Path() path.Path })
+	if !ok || !withPath.Path().Equal(path.Root("manifest").AtName("spec").AtName("replicas")) {
+		t.Fatalf("expected diagnostic on manifest.spec.replicas, got %v", diags[0])
+	}
+
+	if d := applyErrorDiagnostics(errors.New("boom"), diagnosticsTestManifest); d != nil {
+		t.Fatalf("expected no diagnostics for a plain error, got %v", d)
+	}
+	notFound := k8s_errors.NewNotFound(schema.GroupResource{Resource: "deployments"}, "web")
+	if d := applyErrorDiagnostics(notFound, diagnosticsTestManifest); d != nil {
+		t.Fatalf("expected no diagnostics for an error without causes, got %v", d)
+	}
+}
+
+func TestIsPermanentApplyError(t *testing.T) {
+	gk := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	gr := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	ssaConflict := &k8s_errors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   409,
+		Reason: metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{{
+			Type:  metav1.CauseTypeFieldManagerConflict,
+			Field: ".spec.replicas",
+		}}},
+	}}
+
+	samples := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"invalid": {
+			err:      k8s_errors.NewInvalid(gk, "web", field.ErrorList{}),
+			expected: true,
+		},
+		"field manager conflict": {
+			err:      fmt.Errorf("failed to apply manifest: %w", ssaConflict),
+			expected: true,
+		},
+		"resource version conflict": {
+			err:      k8s_errors.NewConflict(gr, "web", errors.New("object was modified")),
+			expected: false,
+		},
+		"not found": {
+			err:      k8s_errors.NewNotFound(gr, "web"),
+			expected: false,
+		},
+		"unavailable": {
+			err:      k8s_errors.NewServiceUnavailable("try again"),
+			expected: false,
+		},
+		"plain": {
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			if got := isPermanentApplyError(s.err); got != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsImmutableFieldError(t *testing.T) {
+	gk := schema.GroupKind{Group: "batch", Kind: "Job"}
+	samples := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"field is immutable": {
+			err: k8s_errors.NewInvalid(gk, "migrate", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+			}),
+			expected: true,
+		},
+		"CEL transition rule": {
+			err: k8s_errors.NewInvalid(gk, "migrate", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "mode"), "b", "Value is immutable"),
+			}),
+			expected: true,
+		},
+		"statefulset spec": {
+			err: k8s_errors.NewInvalid(gk, "db", field.ErrorList{
+				field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields "+
+					"other than 'replicas', 'template' and 'updateStrategy' are forbidden"),
+			}),
+			expected: true,
+		},
+		"other invalid value": {
+			err: k8s_errors.NewInvalid(gk, "migrate", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "parallelism"), -1, "must be non-negative"),
+			}),
+		},
+		"wrapped": {
+			err: fmt.Errorf("dry run: %w", k8s_errors.NewInvalid(gk, "migrate", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "selector"), "", "field is immutable"),
+			})),
+			expected: true,
+		},
+		"not invalid": {
+			err: k8s_errors.NewBadRequest("field is immutable"),
+		},
+		"plain": {
+			err: errors.New("field is immutable"),
+		},
+		"nil": {},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			if got := isImmutableFieldError(s.err); got != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, got)
+			}
+		})
+	}
+}
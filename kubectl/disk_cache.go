@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	restclient "k8s.io/client-go/rest"
+)
+
+// defaultCacheTTL is how long on-disk discovery, OpenAPI and CRD data is
+// considered fresh before it is revalidated against the API server.
+const defaultCacheTTL = 10 * time.Minute
+
+// errDocumentNotFound is returned by fetchCached when the API server does not
+// serve the requested document, e.g. /openapi/v3 on clusters older than 1.24.
+var errDocumentNotFound = errors.New("document not found")
+
+var unsafeCacheKeyChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]`)
+
+// diskCache persists API server documents under a directory that is keyed
+// by cluster host and server version. Each entry is stored as the raw body
+// plus a small ".meta" sidecar recording the ETag and the time it was fetched.
+type diskCache struct {
+	dir    string
+	ttl    time.Duration
+	logger hclog.Logger
+}
+
+// diskCacheMeta is the sidecar stored next to each cached document.
+type diskCacheMeta struct {
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// read returns the cached body for key along with its ETag. fresh reports
+// whether the entry is still within the TTL and can be used without a
+// network round-trip. ok is false when there is no usable entry.
+func (c *diskCache) read(key string) (body []byte, etag string, fresh bool, ok bool) {
+	if c == nil {
+		return nil, "", false, false
+	}
+	mb, err := os.ReadFile(c.metaPath(key))
+	if err != nil {
+		return nil, "", false, false
+	}
+	var m diskCacheMeta
+	if err := json.Unmarshal(mb, &m); err != nil {
+		return nil, "", false, false
+	}
+	body, err = os.ReadFile(c.bodyPath(key))
+	if err != nil {
+		return nil, "", false, false
+	}
+	return body, m.ETag, time.Since(m.FetchedAt) < c.ttl, true
+}
+
+// write stores body and its ETag under key, replacing any previous entry.
+func (c *diskCache) write(key string, body []byte, etag string) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.bodyPath(key)), 0o750); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(c.bodyPath(key), body); err != nil {
+		return err
+	}
+	return c.touch(key, etag)
+}
+
+// touch marks the entry for key as freshly validated without rewriting its body.
+func (c *diskCache) touch(key, etag string) error {
+	if c == nil {
+		return nil
+	}
+	mb, err := json.Marshal(diskCacheMeta{ETag: etag, FetchedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.metaPath(key), mb)
+}
+
+// invalidate removes the entry for key. Missing entries are not an error.
+func (c *diskCache) invalidate(key string) {
+	if c == nil {
+		return
+	}
+	for _, p := range []string{c.bodyPath(key), c.metaPath(key)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			c.warn("failed to remove cache entry %s: %s", p, err)
+		}
+	}
+}
+
+func (c *diskCache) bodyPath(key string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(key))
+}
+
+func (c *diskCache) metaPath(key string) string {
+	return c.bodyPath(key) + ".meta"
+}
+
+func (c *diskCache) warn(format string, args ...any) {
+	if c.logger != nil {
+		c.logger.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// sanitizeCacheKey makes an arbitrary string safe to use as a single path
+// element, e.g. a server version such as "v1.31.2+k3s1".
+func sanitizeCacheKey(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return unsafeCacheKeyChars.ReplaceAllString(s, "_")
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// renames it into place, so concurrent provider processes never observe a
+// partially written document.
+func writeFileAtomic(name string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// fetchCached returns the document at the given absolute API path. When the
+// cache holds a fresh copy it is returned without contacting the server.
+// A stale copy is revalidated with If-None-Match, so an unchanged document
+// costs a single 304 response instead of a full download.
+func fetchCached(
+	ctx context.Context,
+	cfg *restclient.Config,
+	c *diskCache,
+	key string,
+	absPath ...string,
+) ([]byte, error) {
+	cached, etag, fresh, ok := c.read(key)
+	if ok && fresh {
+		return cached, nil
+	}
+
+	hc, err := restclient.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	u, _, err := restclient.DefaultServerUrlFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API server host %q: %w", cfg.Host, err)
+	}
+	u.Path = path.Join(append([]string{u.Path}, absPath...)...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if ok && etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	rs, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Body.Close()
+
+	switch {
+	case rs.StatusCode == http.StatusNotModified && ok:
+		if err := c.touch(key, etag); err != nil {
+			c.warn("failed to refresh cache entry %s: %s", key, err)
+		}
+		return cached, nil
+	case rs.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("GET %s: %w", u.Path, errDocumentNotFound)
+	case rs.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("GET %s returned %s", u.Path, rs.Status)
+	}
+
+	body, err := io.ReadAll(rs.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.write(key, body, rs.Header.Get("ETag")); err != nil {
+		c.warn("failed to write cache entry %s: %s", key, err)
+	}
+	return body, nil
+}
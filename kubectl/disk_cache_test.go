@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	restclient "k8s.io/client-go/rest"
+)
+
+func TestDiskCacheReadWrite(t *testing.T) {
+	c := &diskCache{dir: t.TempDir(), ttl: time.Minute}
+
+	if _, _, _, ok := c.read("openapi/v2"); ok {
+		t.Fatal("expected empty cache to miss")
+	}
+
+	if err := c.write("openapi/v2", []byte("doc"), `"abc"`); err != nil {
+		t.Fatalf("write: %s", err)
+	}
+	body, etag, fresh, ok := c.read("openapi/v2")
+	if !ok || !fresh || string(body) != "doc" || etag != `"abc"` {
+		t.Fatalf("unexpected entry: body=%q etag=%q fresh=%t ok=%t", body, etag, fresh, ok)
+	}
+
+	c.ttl = 0
+	if _, _, fresh, ok := c.read("openapi/v2"); !ok || fresh {
+		t.Fatalf("expected stale entry, got fresh=%t ok=%t", fresh, ok)
+	}
+
+	c.invalidate("openapi/v2")
+	if _, _, _, ok := c.read("openapi/v2"); ok {
+		t.Fatal("expected invalidated entry to miss")
+	}
+}
+
+func TestFetchCachedRevalidates(t *testing.T) {
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/openapi/v2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"swagger":"2.0"}`))
+	}))
+	defer srv.Close()
+
+	cfg := &restclient.Config{Host: srv.URL}
+	c := &diskCache{dir: t.TempDir(), ttl: time.Hour}
+	ctx := context.Background()
+
+	for range 2 {
+		body, err := fetchCached(ctx, cfg, c, "openapi/v2", "openapi", "v2")
+		if err != nil {
+			t.Fatalf("fetch: %s", err)
+		}
+		if string(body) != `{"swagger":"2.0"}` {
+			t.Fatalf("unexpected body %q", body)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected fresh entry to be served from disk, got %d requests", requests)
+	}
+
+	c.ttl = 0
+	body, err := fetchCached(ctx, cfg, c, "openapi/v2", "openapi", "v2")
+	if err != nil {
+		t.Fatalf("fetch: %s", err)
+	}
+	if string(body) != `{"swagger":"2.0"}` || notModified != 1 {
+		t.Fatalf("expected revalidation, body=%q notModified=%d", body, notModified)
+	}
+}
+
+func TestSanitizeCacheKey(t *testing.T) {
+	if got := sanitizeCacheKey("v1.31.2+k3s1"); got != "v1.31.2_k3s1" {
+		t.Fatalf("unexpected key %q", got)
+	}
+	if got := sanitizeCacheKey(""); got != "unknown" {
+		t.Fatalf("unexpected key %q", got)
+	}
+}
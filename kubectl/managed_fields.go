@@ -0,0 +1,387 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v6/value"
+)
+
+// fieldOwnership records which fields of a live object are owned by which
+// field managers, decoded from the fieldsV1 sets in its metadata.managedFields.
+type fieldOwnership struct {
+	// owned holds the fields last applied by our field manager.
+	owned *fieldpath.Set
+	// others holds the fields owned by every other manager, by manager name.
+	others map[string]*fieldpath.Set
+}
+
+// fieldDrift is a field of the manifest whose live value differs from the
+// last applied one.
+type fieldDrift struct {
+	Path *tftypes.AttributePath
+	// Manager is the field manager owning the live value, if known.
+	Manager string
+	// Owned reports whether our field manager still owns the field.
+	Owned bool
+}
+
+// takenOver reports whether another field manager now owns the field.
+func (d fieldDrift) takenOver() bool {
+	return !d.Owned && d.Manager != ""
+}
+
+// decodeFieldOwnership decodes the managed fields of object. It returns nil
+// when manager has never applied the object, or the named subresource of it,
+// e.g. right after an import, in which case every field of the manifest is
+// treated as ours.
+func decodeFieldOwnership(
+	object map[string]any,
+	manager, subresource string,
+) (*fieldOwnership, error) {
+	uo := meta_v1_unstruct.Unstructured{Object: object}
+	o := &fieldOwnership{others: map[string]*fieldpath.Set{}}
+	for _, e := range uo.GetManagedFields() {
+		if e.FieldsType != "FieldsV1" || e.FieldsV1 == nil {
+			continue
+		}
+		s := &fieldpath.Set{}
+		if err := s.FromJSON(bytes.NewReader(e.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("failed to decode managed fields of %q: %w", e.Manager, err)
+		}
+		if e.Manager == manager {
+			if e.Operation == meta_v1.ManagedFieldsOperationApply && e.Subresource == subresource {
+				o.owned = s
+			}
+			continue
+		}
+		if prev, ok := o.others[e.Manager]; ok {
+			s = prev.Union(s)
+		}
+		o.others[e.Manager] = s
+	}
+	if o.owned == nil {
+		return nil, nil
+	}
+	return o, nil
+}
+
+// fieldOwnershipFromObject decodes the managed fields of the live object held
+// in the object attribute for the field manager configured on model.
+func fieldOwnershipFromObject(
+	ctx context.Context,
+	model *manifestResourceModel,
+) (*fieldOwnership, error) {
+	object, d := dynamicToMap(ctx, model.Object)
+	if d.HasError() {
+		return nil, fmt.Errorf("failed to convert object: %v", d)
+	}
+	if object == nil {
+		return nil, nil
+	}
+	manager, _, err := fieldManagerSettings(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFieldOwnership(object, manager, subresourceName(model.Subresource))
+}
+
+// reconcile is the ownership-aware counterpart of deepReconcileMaps. Fields
+// of prior that our manager still owns take their live value; all others keep
+// their prior value, so that changes made by controllers, autoscalers and
+// mutating webhooks to fields they own never show up as drift. A nil
+// fieldOwnership treats every field as ours.
+//
+// The fields whose live value differs from prior are returned, sorted by path.
+func (o *fieldOwnership) reconcile(
+	prior, live map[string]any,
+) (map[string]any, []fieldDrift) {
+	var owned *fieldpath.Set
+	var others map[string]*fieldpath.Set
+	if o != nil {
+		owned, others = o.owned, o.others
+	}
+	w := &ownershipWalker{}
+	result := w.maps(prior, live, owned, others, tftypes.NewAttributePath())
+	sort.Slice(w.drift, func(i, j int) bool {
+		return api.FieldPathString(w.drift[i].Path) < api.FieldPathString(w.drift[j].Path)
+	})
+	return result, w.drift
+}
+
+// ownershipWalker walks prior and live values alongside the field sets of
+// our manager (owned) and of every other manager. A nil owned set means that
+// everything below it is ours.
+type ownershipWalker struct {
+	drift []fieldDrift
+}
+
+func (w *ownershipWalker) maps(
+	prior, live map[string]any,
+	owned *fieldpath.Set,
+	others map[string]*fieldpath.Set,
+	ap *tftypes.AttributePath,
+) map[string]any {
+	result := make(map[string]any, len(prior))
+	for k, priorVal := range prior {
+		liveVal, ok := live[k]
+		if !ok {
+			result[k] = priorVal
+			continue
+		}
+		pe := fieldpath.FieldNameElement(k)
+		result[k] = w.value(priorVal, liveVal, pe, owned, others, ap.WithAttributeName(k))
+	}
+	return result
+}
+
+func (w *ownershipWalker) slices(
+	prior, live []any,
+	owned *fieldpath.Set,
+	others map[string]*fieldpath.Set,
+	ap *tftypes.AttributePath,
+) []any {
+	result := make([]any, len(prior))
+	for i, priorElem := range prior {
+		var pe fieldpath.PathElement
+		j := -1
+		if owned == nil {
+			if i < len(live) {
+				j = i
+			}
+		} else if e, ok := listElement(owned, priorElem, i); ok {
+			// Items of map-type lists are matched by their keys rather than
+			// by index, so that items added by others do not shift ours.
+			pe, j = e, indexOfListElement(live, e)
+		}
+		if j < 0 {
+			result[i] = priorElem
+			continue
+		}
+		result[i] = w.value(priorElem, live[j], pe, owned, others, ap.WithElementKeyInt(i))
+	}
+	return result
+}
+
+func (w *ownershipWalker) value(
+	priorVal, liveVal any,
+	pe fieldpath.PathElement,
+	owned *fieldpath.Set,
+	others map[string]*fieldpath.Set,
+	ap *tftypes.AttributePath,
+) any {
+	child, descend := owned, owned == nil
+	if owned != nil {
+		child, descend = owned.Children.Get(pe)
+	}
+	if descend {
+		nested := make(map[string]*fieldpath.Set, len(others))
+		for m, s := range others {
+			if cs, ok := s.Children.Get(pe); ok {
+				nested[m] = cs
+			}
+		}
+		switch pv := priorVal.(type) {
+		case map[string]any:
+			if lv, ok := liveVal.(map[string]any); ok {
+				return w.maps(pv, lv, child, nested, ap)
+			}
+		case []any:
+			if lv, ok := liveVal.([]any); ok {
+				return w.slices(pv, lv, child, nested, ap)
+			}
+		}
+	}
+
+	ours := descend || owned.Members.Has(pe)
+	result := reconcileValue(priorVal, liveVal)
+	if liveVal != nil && !reflect.DeepEqual(result, priorVal) {
+		w.drift = append(w.drift, fieldDrift{
+			Path:    ap,
+			Manager: ownerOf(others, pe),
+			Owned:   ours,
+		})
+	}
+	if !ours {
+		return priorVal
+	}
+	return result
+}
+
+// ownerOf returns the first of others, by name, that owns pe or any field
+// below it.
+func ownerOf(others map[string]*fieldpath.Set, pe fieldpath.PathElement) string {
+	for _, m := range sortedManagers(others) {
+		s := others[m]
+		if _, ok := s.Children.Get(pe); ok || s.Members.Has(pe) {
+			return m
+		}
+	}
+	return ""
+}
+
+// listElement finds the path element s uses for item, the i-th item of a list:
+// its key fields for map-type lists, its value for set-type lists, or its index.
+func listElement(s *fieldpath.Set, item any, i int) (fieldpath.PathElement, bool) {
+	var found fieldpath.PathElement
+	ok := false
+	match := func(pe fieldpath.PathElement) {
+		if !ok && listElementMatches(pe, item, i) {
+			found, ok = pe, true
+		}
+	}
+	s.Children.Iterate(match)
+	s.Members.Iterate(match)
+	return found, ok
+}
+
+// indexOfListElement returns the index of the item of list identified by pe,
+// or -1.
+func indexOfListElement(list []any, pe fieldpath.PathElement) int {
+	for i, item := range list {
+		if listElementMatches(pe, item, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func listElementMatches(pe fieldpath.PathElement, item any, i int) bool {
+	switch {
+	case pe.Index != nil:
+		return *pe.Index == i
+	case pe.Value != nil:
+		return value.Equals(*pe.Value, value.NewValueInterface(item))
+	case pe.Key != nil:
+		m, ok := item.(map[string]any)
+		if !ok {
+			return false
+		}
+		for _, f := range *pe.Key {
+			v, ok := m[f.Name]
+			if !ok || !value.Equals(f.Value, value.NewValueInterface(v)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func sortedManagers(sets map[string]*fieldpath.Set) []string {
+	managers := make([]string, 0, len(sets))
+	for m := range sets {
+		managers = append(managers, m)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+// formatFieldTakeovers describes the fields of drift taken over by other
+// managers for a warning diagnostic, or returns "" if there are none.
+func formatFieldTakeovers(drift []fieldDrift) string {
+	var b strings.Builder
+	for _, d := range drift {
+		if d.takenOver() {
+			fmt.Fprintf(&b, "\n  - %s (%s)", api.FieldPathString(d.Path), d.Manager)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "The following fields of the manifest are now owned by other field " +
+		"managers. Their live values differ from the last applied ones, but Terraform " +
+		"does not plan to revert them:\n" + b.String()
+}
+
+// driftToList converts drift into the value of the drift attribute.
+func driftToList(ctx context.Context, drift []fieldDrift) (types.List, diag.Diagnostics) {
+	entries := make([]driftModel, 0, len(drift))
+	for _, d := range drift {
+		manager := types.StringNull()
+		if d.Manager != "" {
+			manager = types.StringValue(d.Manager)
+		}
+		entries = append(entries, driftModel{
+			Path:    types.StringValue(api.FieldPathString(d.Path)),
+			Manager: manager,
+		})
+	}
+	return types.ListValueFrom(ctx, types.ObjectType{AttrTypes: driftAttrTypes()}, entries)
+}
+
+// fieldManagerMigrateFrom returns the field managers listed in
+// field_manager.migrate_from.
+func fieldManagerMigrateFrom(ctx context.Context, model *manifestResourceModel) ([]string, error) {
+	if model.FieldManager.IsNull() || model.FieldManager.IsUnknown() {
+		return nil, nil
+	}
+	var fm fieldManagerModel
+	diags := model.FieldManager.As(ctx, &fm, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed to parse field_manager: %v", diags)
+	}
+	if fm.MigrateFrom.IsNull() || fm.MigrateFrom.IsUnknown() {
+		return nil, nil
+	}
+	var from []string
+	if diags := fm.MigrateFrom.ElementsAs(ctx, &from, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to parse field_manager.migrate_from: %v", diags)
+	}
+	return from, nil
+}
+
+// migrateFieldManagers hands the fields the client-side managers in from own
+// on the named object over to manager, the way kubectl does when an object
+// moves from client-side to server-side apply, so that the next apply by
+// manager can remove fields no longer in its manifest. Objects that do not
+// exist yet and objects with nothing to migrate are left alone.
+func migrateFieldManagers(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	name string,
+	from []string,
+	manager string,
+) error {
+	live, err := rs.Get(ctx, name, meta_v1.GetOptions{})
+	if k8s_errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s for field manager migration: %w", name, err)
+	}
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(from...), manager)
+	if err != nil {
+		return fmt.Errorf("failed to migrate field managers of %s: %w", name, err)
+	}
+	if patch == nil {
+		return nil
+	}
+	// The patch replaces the resource version as well, so it fails rather
+	// than overwrite managed fields that changed since they were read.
+	_, err = rs.Patch(ctx, name, k8stypes.JSONPatchType, patch, meta_v1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to migrate field managers of %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const managedFieldsTestObject = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "web",
+    "managedFields": [
+      {
+        "manager": "Terraform",
+        "operation": "Apply",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {
+          "f:metadata": {"f:labels": {"f:app": {}}},
+          "f:spec": {
+            "f:template": {"f:spec": {"f:containers": {
+              "k:{\"name\":\"app\"}": {".": {}, "f:name": {}, "f:image": {}}
+            }}}
+          }
+        }
+      },
+      {
+        "manager": "kube-controller-manager",
+        "operation": "Update",
+        "subresource": "scale",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:spec": {"f:replicas": {}}}
+      },
+      {
+        "manager": "kubectl-edit",
+        "operation": "Update",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:metadata": {"f:labels": {"f:tier": {}}}}
+      },
+      {
+        "manager": "Terraform",
+        "operation": "Update",
+        "subresource": "status",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:status": {"f:replicas": {}}}
+      }
+    ]
+  }
+}`
+
+func TestDecodeFieldOwnership(t *testing.T) {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(managedFieldsTestObject), &object); err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := decodeFieldOwnership(object, "Terraform", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o == nil {
+		t.Fatal("expected ownership for Terraform")
+	}
+	if got := sortedManagers(o.others); !reflect.DeepEqual(
+		got, []string{"kube-controller-manager", "kubectl-edit"},
+	) {
+		t.Fatalf("unexpected other managers %v", got)
+	}
+
+	if o, err := decodeFieldOwnership(object, "other", ""); err != nil || o != nil {
+		t.Fatalf("expected no ownership for a manager that never applied, got %v, %v", o, err)
+	}
+}
+
+func TestFieldOwnershipReconcile(t *testing.T) {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(managedFieldsTestObject), &object); err != nil {
+		t.Fatal(err)
+	}
+	o, err := decodeFieldOwnership(object, "Terraform", "")
+	if err != nil || o == nil {
+		t.Fatalf("failed to decode ownership: %v", err)
+	}
+
+	prior := map[string]any{
+		"metadata": map[string]any{
+			"name":   "web",
+			"labels": map[string]any{"app": "web", "tier": "frontend"},
+		},
+		"spec": map[string]any{
+			"replicas": float64(1),
+			"template": map[string]any{"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "app", "image": "nginx:1.27"},
+				},
+			}},
+		},
+	}
+	live := map[string]any{
+		"metadata": map[string]any{
+			"name":   "web",
+			"labels": map[string]any{"app": "web-2", "tier": "backend"},
+		},
+		"spec": map[string]any{
+			"replicas": float64(5),
+			"template": map[string]any{"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "sidecar", "image": "envoy"},
+					map[string]any{"name": "app", "image": "nginx:1.28"},
+				},
+			}},
+		},
+	}
+
+	result, drift := o.reconcile(prior, live)
+
+	expected := map[string]any{
+		"metadata": map[string]any{
+			"name":   "web",
+			"labels": map[string]any{"app": "web-2", "tier": "frontend"},
+		},
+		"spec": map[string]any{
+			"replicas": float64(1),
+			"template": map[string]any{"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "app", "image": "nginx:1.28"},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result:\n got: %v\nwant: %v", result, expected)
+	}
+
+	got := make([]string, 0, len(drift))
+	for _, d := range drift {
+		got = append(got, fmt.Sprintf("%s %q %t", api.FieldPathString(d.Path), d.Manager, d.Owned))
+	}
+	want := []string{
+		`metadata.labels.app "" true`,
+		`metadata.labels.tier "kubectl-edit" false`,
+		`spec.replicas "kube-controller-manager" false`,
+		`spec.template.spec.containers[0].image "" true`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected drift:\n got: %v\nwant: %v", got, want)
+	}
+
+	msg := formatFieldTakeovers(drift)
+	if !strings.Contains(msg, "spec.replicas (kube-controller-manager)") ||
+		strings.Contains(msg, "metadata.labels.app") {
+		t.Fatalf("unexpected takeover message %q", msg)
+	}
+}
+
+func TestFieldOwnershipReconcileWithoutManagedFields(t *testing.T) {
+	var o *fieldOwnership
+	prior := map[string]any{
+		"data":  map[string]any{"a": "1", "b": "2"},
+		"items": []any{"x", "y"},
+	}
+	live := map[string]any{
+		"data":  map[string]any{"a": "1", "b": "3", "c": "4"},
+		"items": []any{"x", "z", "extra"},
+	}
+
+	result, drift := o.reconcile(prior, live)
+
+	expected := map[string]any{
+		"data":  map[string]any{"a": "1", "b": "3"},
+		"items": []any{"x", "z"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("unexpected result:\n got: %v\nwant: %v", result, expected)
+	}
+	var got []string
+	for _, d := range drift {
+		got = append(got, api.FieldPathString(d.Path))
+		if d.Manager != "" || !d.Owned {
+			t.Fatalf("unexpected drift entry %+v", d)
+		}
+	}
+	if want := []string{"data.b", "items[1]"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected drift %v", got)
+	}
+	if msg := formatFieldTakeovers(drift); msg != "" {
+		t.Fatalf("expected no takeovers, got %q", msg)
+	}
+}
+
+const clientSideAppliedObject = `{
+  "apiVersion": "v1",
+  "kind": "ConfigMap",
+  "metadata": {
+    "name": "settings",
+    "namespace": "default",
+    "resourceVersion": "7",
+    "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+    "managedFields": [{
+      "manager": "kubectl-client-side-apply",
+      "operation": "Update",
+      "apiVersion": "v1",
+      "fieldsType": "FieldsV1",
+      "fieldsV1": {
+        "f:data": {"f:mode": {}, "f:legacy": {}},
+        "f:metadata": {"f:annotations": {
+          "f:kubectl.kubernetes.io/last-applied-configuration": {}
+        }}
+      }
+    }]
+  },
+  "data": {"mode": "fast", "legacy": "true"}
+}`
+
+func TestMigrateFieldManagers(t *testing.T) {
+	ctx := context.Background()
+	live := &unstructured.Unstructured{}
+	if err := live.UnmarshalJSON([]byte(clientSideAppliedObject)); err != nil {
+		t.Fatal(err)
+	}
+	gvr := k8sschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live)
+	rs := client.Resource(gvr).Namespace("default")
+
+	from := []string{"kubectl-client-side-apply"}
+	if err := migrateFieldManagers(ctx, rs, "settings", from, "Terraform"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	migrated, err := rs.Get(ctx, "settings", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := decodeFieldOwnership(migrated.Object, "Terraform", "")
+	if err != nil || o == nil {
+		t.Fatalf("expected fields to be owned by Terraform, got %v, %v", o, err)
+	}
+	if len(o.others) != 0 {
+		t.Fatalf("expected no other field managers, got %v", sortedManagers(o.others))
+	}
+	if !strings.Contains(o.owned.String(), ".data.legacy") {
+		t.Fatalf("expected .data.legacy to be owned by Terraform, got %s", o.owned)
+	}
+
+	// nothing is left to migrate, and missing objects are skipped
+	for _, name := range []string{"settings", "missing"} {
+		if err := migrateFieldManagers(ctx, rs, name, from, "Terraform"); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestFieldManagerMigrateFrom(t *testing.T) {
+	ctx := context.Background()
+	model := &manifestResourceModel{FieldManager: types.ObjectNull(fieldManagerBlockAttrTypes())}
+	if from, err := fieldManagerMigrateFrom(ctx, model); err != nil || from != nil {
+		t.Fatalf("expected no managers, got %v, %v", from, err)
+	}
+
+	model.FieldManager = types.ObjectValueMust(fieldManagerBlockAttrTypes(), map[string]attr.Value{
+		"name":            types.StringValue("Terraform"),
+		"force_conflicts": types.BoolValue(false),
+		"migrate_from": types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("kubectl-client-side-apply"),
+			types.StringValue("kubectl"),
+		}),
+	})
+	from, err := fieldManagerMigrateFrom(ctx, model)
+	if err != nil || !reflect.DeepEqual(from, []string{"kubectl-client-side-apply", "kubectl"}) {
+		t.Fatalf("unexpected managers %v, %v", from, err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// Values of create_policy, which decides whether Create may take over an
+// object that already exists.
+const (
+	createPolicyAdopt        = "adopt"
+	createPolicyFailIfExists = "fail_if_exists"
+	createPolicyAdoptIfOwned = "adopt_if_owned"
+)
+
+// ownerAnnotation records the field manager of the resource that created an
+// object, so that adopt_if_owned can recognise objects it created before.
+const ownerAnnotation = "kubectl.terraform.io/owner"
+
+// ObjectExistsError is returned by Create when the object already exists and
+// create_policy does not allow adopting it.
+type ObjectExistsError struct {
+	Kind  string
+	Name  string
+	Owner string
+}
+
+func (e *ObjectExistsError) Error() string {
+	msg := fmt.Sprintf("%s %q already exists", e.Kind, e.Name)
+	if e.Owner != "" {
+		msg += fmt.Sprintf(" and is owned by %q", e.Owner)
+	}
+	return msg + "; import it or set create_policy to adopt it"
+}
+
+// createPolicy returns the create_policy of model, adopt when unset.
+func createPolicy(model *manifestResourceModel) string {
+	if model.CreatePolicy.IsNull() || model.CreatePolicy.IsUnknown() {
+		return createPolicyAdopt
+	}
+	return model.CreatePolicy.ValueString()
+}
+
+// setOwnerAnnotation marks uo as created by manager unless the policy adopts
+// any object, or the manifest sets the annotation itself.
+func setOwnerAnnotation(uo *meta_v1_unstruct.Unstructured, policy, manager string) {
+	if policy == createPolicyAdopt {
+		return
+	}
+	annotations := uo.GetAnnotations()
+	if _, ok := annotations[ownerAnnotation]; ok {
+		return
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ownerAnnotation] = manager
+	uo.SetAnnotations(annotations)
+}
+
+// createForPolicy creates the object model describes, with manifest_wo merged
+// in, when its create_policy does not allow adopting any existing object, and
+// reports whether it did. The object is created with a POST, which unlike a
+// server-side apply fails when the object exists, so that an object created
+// concurrently is never adopted by mistake. An existing object is checked
+// against the policy, and an *ObjectExistsError returned if it may not be
+// adopted.
+//
+// Objects named by metadata.generateName never exist before they are created
+// and are left to createObject.
+func (r *manifestResource) createForPolicy(
+	ctx context.Context,
+	model *manifestResourceModel,
+	manifestWoMap map[string]any,
+) (bool, error) {
+	// Writing to a subresource never creates the object.
+	policy := createPolicy(model)
+	if policy == createPolicyAdopt || subresourceName(model.Subresource) != "" {
+		return false, nil
+	}
+	uo, diags := buildUnstructured(ctx, model)
+	if diags.HasError() {
+		return false, fmt.Errorf("failed to build unstructured: %v", diags)
+	}
+	if uo.GetName() == "" {
+		return false, nil
+	}
+	name, err := r.createObject(ctx, model, manifestWoMap)
+	if !k8s_errors.IsAlreadyExists(err) {
+		return name != "", err
+	}
+	manager, _, err := fieldManagerSettings(ctx, model)
+	if err != nil {
+		return false, err
+	}
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, yaml.NewFromUnstructured(uo))
+	if restClient.Error != nil {
+		return false, fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
+	}
+	return false, checkExistingObject(ctx, restClient.ResourceInterface, uo.GetKind(),
+		uo.GetName(), policy, manager)
+}
+
+// checkExistingObject looks up the named object and returns an
+// *ObjectExistsError if it exists and policy does not allow manager to adopt
+// it.
+func checkExistingObject(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	kind, name, policy, manager string,
+) error {
+	if policy == createPolicyAdopt {
+		return nil
+	}
+	live, err := rs.Get(ctx, name, meta_v1.GetOptions{})
+	if k8s_errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check for an existing %s %q: %w", kind, name, err)
+	}
+	owner := live.GetAnnotations()[ownerAnnotation]
+	if policy == createPolicyAdoptIfOwned && owner == manager {
+		return nil
+	}
+	return &ObjectExistsError{Kind: kind, Name: name, Owner: owner}
+}
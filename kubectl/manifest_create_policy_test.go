@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestCheckExistingObject(t *testing.T) {
+	ctx := context.Background()
+	configMap := func(name string, annotations map[string]string) *meta_v1_unstruct.Unstructured {
+		uo := &meta_v1_unstruct.Unstructured{}
+		uo.SetAPIVersion("v1")
+		uo.SetKind("ConfigMap")
+		uo.SetNamespace("default")
+		uo.SetName(name)
+		uo.SetAnnotations(annotations)
+		return uo
+	}
+	gvr := k8sschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		configMap("unowned", nil),
+		configMap("owned", map[string]string{ownerAnnotation: "Terraform"}),
+	)
+	rs := client.Resource(gvr).Namespace("default")
+
+	samples := map[string]struct {
+		name    string
+		policy  string
+		manager string
+		exists  bool
+		owner   string
+	}{
+		"adopt existing": {
+			name:   "unowned",
+			policy: createPolicyAdopt,
+		},
+		"fail if exists, missing": {
+			name:   "missing",
+			policy: createPolicyFailIfExists,
+		},
+		"fail if exists": {
+			name:   "unowned",
+			policy: createPolicyFailIfExists,
+			exists: true,
+		},
+		"fail if exists, owned": {
+			name:   "owned",
+			policy: createPolicyFailIfExists,
+			exists: true,
+			owner:  "Terraform",
+		},
+		"adopt if owned": {
+			name:   "owned",
+			policy: createPolicyAdoptIfOwned,
+		},
+		"adopt if owned, missing": {
+			name:   "missing",
+			policy: createPolicyAdoptIfOwned,
+		},
+		"adopt if owned, unowned": {
+			name:   "unowned",
+			policy: createPolicyAdoptIfOwned,
+			exists: true,
+		},
+		"adopt if owned by another manager": {
+			name:    "owned",
+			policy:  createPolicyAdoptIfOwned,
+			manager: "other",
+			exists:  true,
+			owner:   "Terraform",
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			manager := s.manager
+			if manager == "" {
+				manager = "Terraform"
+			}
+			err := checkExistingObject(ctx, rs, "ConfigMap", s.name, s.policy, manager)
+			var oee *ObjectExistsError
+			if !s.exists {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &oee) {
+				t.Fatalf("expected ObjectExistsError, got %v", err)
+			}
+			if oee.Name != s.name || oee.Owner != s.owner {
+				t.Fatalf("unexpected error %+v", oee)
+			}
+		})
+	}
+}
+
+func TestSetOwnerAnnotation(t *testing.T) {
+	samples := map[string]struct {
+		policy      string
+		annotations map[string]string
+		expected    map[string]string
+	}{
+		"adopt": {
+			policy: createPolicyAdopt,
+		},
+		"fail if exists": {
+			policy:   createPolicyFailIfExists,
+			expected: map[string]string{ownerAnnotation: "Terraform"},
+		},
+		"adopt if owned keeps other annotations": {
+			policy:      createPolicyAdoptIfOwned,
+			annotations: map[string]string{"team": "web"},
+			expected:    map[string]string{"team": "web", ownerAnnotation: "Terraform"},
+		},
+		"set in manifest": {
+			policy:      createPolicyAdoptIfOwned,
+			annotations: map[string]string{ownerAnnotation: "shared"},
+			expected:    map[string]string{ownerAnnotation: "shared"},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			uo := &meta_v1_unstruct.Unstructured{Object: map[string]any{}}
+			uo.SetAnnotations(s.annotations)
+			setOwnerAnnotation(uo, s.policy, "Terraform")
+			if got := uo.GetAnnotations(); !reflect.DeepEqual(got, s.expected) {
+				t.Fatalf("expected %v, got %v", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestCreateForPolicy(t *testing.T) {
+	ctx := context.Background()
+	existing := &meta_v1_unstruct.Unstructured{}
+	existing.SetAPIVersion("v1")
+	existing.SetKind("ConfigMap")
+	existing.SetNamespace("default")
+	existing.SetName("taken")
+	existing.SetAnnotations(map[string]string{ownerAnnotation: "web"})
+
+	samples := map[string]struct {
+		name    string
+		policy  string
+		created bool
+		exists  bool
+	}{
+		"adopt":                   {name: "taken", policy: createPolicyAdopt},
+		"fail if exists, missing": {name: "new", policy: createPolicyFailIfExists, created: true},
+		"fail if exists":          {name: "taken", policy: createPolicyFailIfExists, exists: true},
+		"adopt if owned":          {name: "taken", policy: createPolicyAdoptIfOwned},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(k8sschema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				meta.RESTScopeNamespace)
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), existing.DeepCopy())
+			p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+			_, _ = p.restMapper.Get(func() (meta.RESTMapper, error) { return mapper, nil })
+			_, _ = p.dynamicClient.Get(func() (dynamic.Interface, error) { return client, nil })
+			r := &manifestResource{providerData: p}
+
+			manifest, d := mapToDynamic(ctx, map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": s.name, "namespace": "default"},
+			})
+			if d.HasError() {
+				t.Fatal(d)
+			}
+			model := &manifestResourceModel{
+				Manifest:     manifest,
+				CreatePolicy: types.StringValue(s.policy),
+				Subresource:  types.StringNull(),
+				FieldManager: types.ObjectValueMust(fieldManagerBlockAttrTypes(),
+					map[string]attr.Value{
+						"name":            types.StringValue("web"),
+						"force_conflicts": types.BoolNull(),
+						"migrate_from":    types.ListNull(types.StringType),
+					}),
+			}
+
+			created, err := r.createForPolicy(ctx, model, nil)
+			var oee *ObjectExistsError
+			if s.exists != errors.As(err, &oee) || (!s.exists && err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created != s.created {
+				t.Fatalf("expected created to be %t, got %t", s.created, created)
+			}
+			if !s.created {
+				return
+			}
+			gvr := k8sschema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+			live, err := client.Resource(gvr).Namespace("default").
+				Get(ctx, s.name, meta_v1.GetOptions{})
+			if err != nil {
+				t.Fatalf("expected the object to be created: %v", err)
+			}
+			if owner := live.GetAnnotations()[ownerAnnotation]; owner != "web" {
+				t.Fatalf("expected the owner annotation to be set, got %q", owner)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/structured-merge-diff/v6/fieldpath"
+)
+
+// planDryRunEnabled reports whether plan_dry_run is set on the resource or,
+// when unset there, on the provider.
+func (r *manifestResource) planDryRunEnabled(model *manifestResourceModel) bool {
+	if !model.PlanDryRun.IsNull() && !model.PlanDryRun.IsUnknown() {
+		return model.PlanDryRun.ValueBool()
+	}
+	return r.providerData != nil && r.providerData.planDryRun
+}
+
+// fieldManagerSettings returns the field manager name and force_conflicts
+// setting configured in the field_manager block.
+func fieldManagerSettings(
+	ctx context.Context,
+	model *manifestResourceModel,
+) (string, bool, error) {
+	name := "Terraform"
+	force := false
+	if model.FieldManager.IsNull() || model.FieldManager.IsUnknown() {
+		return name, force, nil
+	}
+	var fm fieldManagerModel
+	diags := model.FieldManager.As(ctx, &fm, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return "", false, fmt.Errorf("failed to parse field_manager: %v", diags)
+	}
+	if !fm.Name.IsNull() {
+		name = fm.Name.ValueString()
+	}
+	if !fm.ForceConflicts.IsNull() {
+		force = fm.ForceConflicts.ValueBool()
+	}
+	return name, force, nil
+}
+
+// dryRunPlannedObject returns the object the API server would persist for the
+// planned manifest, obtained with a server-side apply in dry-run mode, so that
+// defaults and mutating admission webhooks show up in the plan. Rejections by
+// validating webhooks, quota and the like are returned as errors.
+//
+// The result is unknown when plan_dry_run is disabled, the provider or the
+// manifest are not fully known yet, or the object cannot be dry-run yet because
+// something it depends on, such as its namespace, does not exist. Within it,
+// fields the real apply may set differently are unknown; see
+// plannedObjectFromDryRun.
+func (r *manifestResource) dryRunPlannedObject(
+	ctx context.Context,
+	config tfsdk.Config,
+	plan *manifestResourceModel,
+) (types.Dynamic, diag.Diagnostics) {
+	if !r.planDryRunEnabled(plan) {
+		return types.DynamicUnknown(), nil
+	}
+	manifestMap, woKeys, diags := r.dryRunManifest(ctx, config, plan)
+	if diags.HasError() || manifestMap == nil {
+		return types.DynamicUnknown(), diags
+	}
+	object, d := r.dryRunObject(ctx, plan, manifestMap, woKeys)
+	diags.Append(d...)
+	return object, diags
+}
+
+// dryRunManifest returns the manifest to dry-run for plan, with manifest_wo
+// merged in, and the paths of the write-only fields. The manifest is nil when
+// the provider or the manifest are not fully known yet, or the object has no
+// name yet.
+func (r *manifestResource) dryRunManifest(
+	ctx context.Context,
+	config tfsdk.Config,
+	plan *manifestResourceModel,
+) (map[string]any, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !r.providerData.configFullyKnown {
+		return nil, nil, diags
+	}
+	if v, err := plan.Manifest.ToTerraformValue(ctx); err != nil || !v.IsFullyKnown() {
+		return nil, nil, diags
+	}
+	manifestWo := extractManifestWoFromConfig(ctx, config, &diags)
+	if diags.HasError() || manifestWo.IsUnknown() {
+		return nil, nil, diags
+	}
+	if v, err := manifestWo.ToTerraformValue(ctx); err != nil || !v.IsFullyKnown() {
+		return nil, nil, diags
+	}
+
+	manifestMap, d := dynamicToMap(ctx, plan.Manifest)
+	diags.Append(d...)
+	if diags.HasError() || manifestMap == nil {
+		return nil, nil, diags
+	}
+	// Objects named by metadata.generateName cannot be dry-run before they
+	// are created.
+	if name, _, _ := meta_v1_unstruct.NestedString(manifestMap, "metadata", "name"); name == "" {
+		return nil, nil, diags
+	}
+	var woKeys []string
+	if woMap, _ := dynamicToMap(ctx, manifestWo); woMap != nil {
+		deepMergeMaps(manifestMap, woMap)
+		woKeys = extractLeafPaths(woMap, "")
+	}
+	return manifestMap, woKeys, diags
+}
+
+// immutableFieldChanged reports whether recreate_on_immutable_error is set
+// and a server-side dry run of the planned manifest is rejected because it
+// changes an immutable field. Other rejections are left to plan_dry_run and
+// the apply to report.
+func (r *manifestResource) immutableFieldChanged(
+	ctx context.Context,
+	config tfsdk.Config,
+	plan *manifestResourceModel,
+) (bool, diag.Diagnostics) {
+	if plan.RecreateOnImmutableError.IsNull() || plan.RecreateOnImmutableError.IsUnknown() ||
+		!plan.RecreateOnImmutableError.ValueBool() {
+		return false, nil
+	}
+	manifestMap, _, diags := r.dryRunManifest(ctx, config, plan)
+	if diags.HasError() || manifestMap == nil {
+		return false, diags
+	}
+	fieldManagerName, forceConflicts, err := fieldManagerSettings(ctx, plan)
+	if err != nil {
+		diags.AddError("Invalid field_manager", err.Error())
+		return false, diags
+	}
+	_, err = r.dryRunApply(ctx, plan, manifestMap, fieldManagerName, forceConflicts)
+	if isImmutableFieldError(err) {
+		log.Printf("[DEBUG] Replacing resource, dry run changes an immutable field: %v", err)
+		return true, diags
+	}
+	return false, diags
+}
+
+// dryRunObject applies manifest in dry-run mode and returns the planned
+// object, with the write-only fields at woKeys left out.
+func (r *manifestResource) dryRunObject(
+	ctx context.Context,
+	plan *manifestResourceModel,
+	manifest map[string]any,
+	woKeys []string,
+) (types.Dynamic, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	unknown := types.DynamicUnknown()
+
+	fieldManagerName, forceConflicts, err := fieldManagerSettings(ctx, plan)
+	if err != nil {
+		diags.AddError("Invalid field_manager", err.Error())
+		return unknown, diags
+	}
+
+	result, err := r.dryRunApply(ctx, plan, manifest, fieldManagerName, forceConflicts)
+	if err != nil {
+		diags.Append(applyErrorDiagnostics(err, manifest)...)
+		diags.AddAttributeError(
+			path.Root("manifest"),
+			"Server-side dry run failed",
+			err.Error(),
+		)
+		return unknown, diags
+	}
+	if result == nil {
+		return unknown, diags
+	}
+
+	content := result.UnstructuredContent()
+	for _, key := range woKeys {
+		woDeleteAtPath(content, strings.Split(key, "."))
+	}
+	subresource := subresourceName(plan.Subresource)
+	var create *dryRunCreate
+	if subresource == "" && result.GetResourceVersion() == "" {
+		create = &dryRunCreate{
+			manifest:  manifest,
+			defaulted: r.providerData.schemaDefaulted(ctx, result.GroupVersionKind()),
+		}
+	}
+	planned, err := plannedObjectFromDryRun(content, fieldManagerName, subresource, create)
+	if err != nil {
+		diags.AddError("Failed to plan object from dry run", err.Error())
+		return unknown, diags
+	}
+	obj, d := mapToDynamic(ctx, planned)
+	diags.Append(d...)
+	if diags.HasError() {
+		return unknown, diags
+	}
+	return obj, diags
+}
+
+// dryRunApply sends manifest as a server-side apply in dry-run mode. The
+// result is nil when the object cannot be dry-run yet because its kind or
+// something it depends on, such as its namespace, does not exist.
+func (r *manifestResource) dryRunApply(
+	ctx context.Context,
+	plan *manifestResourceModel,
+	manifest map[string]any,
+	fieldManagerName string,
+	forceConflicts bool,
+) (*meta_v1_unstruct.Unstructured, error) {
+	subresource := subresourceName(plan.Subresource)
+	uo := &meta_v1_unstruct.Unstructured{}
+	uo.SetUnstructuredContent(api.MapRemoveNulls(manifest))
+	setOwnerAnnotation(uo, createPolicy(plan), fieldManagerName)
+	gvk := uo.GroupVersionKind()
+
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, yaml.NewFromUnstructured(uo))
+	if restClient.Error != nil {
+		log.Printf("[DEBUG] Skipping dry run of %s/%s: %v",
+			uo.GetKind(), uo.GetName(), restClient.Error)
+		return nil, nil
+	}
+
+	jsonData, err := json.Marshal(subresourceBody(uo.Object, subresource))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	result, err := restClient.ResourceInterface.Patch(
+		ctx,
+		uo.GetName(),
+		k8stypes.ApplyPatchType,
+		jsonData,
+		meta_v1.PatchOptions{
+			FieldManager: fieldManagerName,
+			Force:        &forceConflicts,
+			DryRun:       []string{meta_v1.DryRunAll},
+		},
+		subresourceArgs(subresource)...,
+	)
+	if k8s_errors.IsNotFound(err) {
+		log.Printf("[DEBUG] Skipping dry run of %s/%s: %v", uo.GetKind(), uo.GetName(), err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the API server rejected %s %q: %w", uo.GetKind(), uo.GetName(), err)
+	}
+	return subresourceObject(result, gvk, subresource), nil
+}
+
+// serverAllocatedFields lists, by kind, the fields the API server allocates
+// when an object is created. A dry run allocates values of its own, which the
+// real apply does not reuse. "*" stands for any list item.
+var serverAllocatedFields = map[k8sschema.GroupKind][][]string{
+	{Kind: "Service"}: {
+		{"spec", "clusterIP"},
+		{"spec", "clusterIPs"},
+		{"spec", "healthCheckNodePort"},
+		{"spec", "ports", "*", "nodePort"},
+	},
+}
+
+// dryRunCreate describes a dry run that creates the object: the manifest it
+// creates the object from, and whether the schema of the object declares a
+// default for the field at a path.
+type dryRunCreate struct {
+	manifest  map[string]any
+	defaulted func(fp []string) bool
+}
+
+// schemaDefaulted returns whether the OpenAPI schema of gvk declares a default
+// for the field at a path. No field has a default when the schema cannot be
+// resolved.
+func (p *kubectlProviderData) schemaDefaulted(
+	ctx context.Context,
+	gvk k8sschema.GroupVersionKind,
+) func(fp []string) bool {
+	sch, defs, err := p.SchemaFromOpenAPI(ctx, gvk)
+	if err != nil {
+		log.Printf("[DEBUG] Could not resolve OpenAPI schema for %s, "+
+			"planning every field added by the API server as unknown: %v", gvk.String(), err)
+		return func([]string) bool { return false }
+	}
+	return func(fp []string) bool { return api.HasSchemaDefault(sch, defs, fp) }
+}
+
+// plannedObjectFromDryRun turns the object returned by a dry run for manager,
+// applying to subresource if set, into the planned value of the object
+// attribute. Fields the real apply may set differently are replaced with
+// unknown values:
+//
+//   - fields owned by other field managers, such as the replicas of a
+//     Deployment scaled by an autoscaler, which may change before the apply,
+//     unless manager owns them as well;
+//   - fields allocated by the API server, unless manager sets them;
+//   - when the dry run creates the object (create is set), the fields the API
+//     server added to the manifest, other than schema defaults. Admission
+//     plugins may add values of their own, such as the randomly named
+//     kube-api-access volume of a Pod, which the real create does not reuse;
+//   - the metadata written with every change, such as the resource version,
+//     and the status.
+//
+// Everything else, in particular the fields of the manifest and the defaults
+// filled in by the API server, is known.
+func plannedObjectFromDryRun(
+	object map[string]any,
+	manager, subresource string,
+	create *dryRunCreate,
+) (map[string]any, error) {
+	owners, err := decodeFieldOwnership(object, manager, subresource)
+	if err != nil {
+		return nil, err
+	}
+	var owned *fieldpath.Set
+	var others map[string]*fieldpath.Set
+	if owners != nil {
+		owned, others = owners.owned, owners.others
+	}
+
+	uo := meta_v1_unstruct.Unstructured{Object: object}
+	p := &dryRunPlanner{allocated: serverAllocatedFields[uo.GroupVersionKind().GroupKind()]}
+	var manifest map[string]any
+	if create != nil {
+		manifest, p.defaulted = create.manifest, create.defaulted
+	}
+	planned, _ := p.value(object, manifest, owned, others, nil).(map[string]any)
+
+	if meta, ok := planned["metadata"].(map[string]any); ok {
+		// A dry run of a create leaves out the resource version.
+		for _, k := range []string{"uid", "resourceVersion", "creationTimestamp", "managedFields"} {
+			meta[k] = unknownValue{}
+		}
+		if _, ok := meta["generation"]; ok {
+			meta["generation"] = unknownValue{}
+		}
+		delete(meta, "selfLink")
+	}
+	if _, ok := planned["status"]; ok {
+		planned["status"] = unknownValue{}
+	}
+	return planned, nil
+}
+
+// dryRunPlanner walks a dry-run result alongside the manifest it was created
+// from, if any, and the fields owned by our field manager (owned) and by every
+// other manager.
+type dryRunPlanner struct {
+	allocated [][]string
+	// defaulted is set when the dry run creates the object.
+	defaulted func(fp []string) bool
+}
+
+func (p *dryRunPlanner) value(
+	val, manifest any,
+	owned *fieldpath.Set,
+	others map[string]*fieldpath.Set,
+	fp []string,
+) any {
+	switch tv := val.(type) {
+	case map[string]any:
+		mm, _ := manifest.(map[string]any)
+		out := make(map[string]any, len(tv))
+		for k, e := range tv {
+			pe := fieldpath.FieldNameElement(k)
+			elem := func(*fieldpath.Set) (fieldpath.PathElement, bool) { return pe, true }
+			out[k] = p.field(e, mm[k], elem, owned, others, append(fp[:len(fp):len(fp)], k))
+		}
+		return out
+	case []any:
+		ml, _ := manifest.([]any)
+		out := make([]any, len(tv))
+		for i, e := range tv {
+			elem := func(s *fieldpath.Set) (fieldpath.PathElement, bool) {
+				return listElement(s, e, i)
+			}
+			var me any
+			if i < len(ml) {
+				me = ml[i]
+			}
+			out[i] = p.field(e, me, elem, owned, others, append(fp[:len(fp):len(fp)], "*"))
+		}
+		return out
+	}
+	return val
+}
+
+func (p *dryRunPlanner) field(
+	val, manifest any,
+	elem func(*fieldpath.Set) (fieldpath.PathElement, bool),
+	owned *fieldpath.Set,
+	others map[string]*fieldpath.Set,
+	fp []string,
+) any {
+	child, ours := ownedField(owned, elem)
+	nested := make(map[string]*fieldpath.Set, len(others))
+	theirs := false
+	for m, s := range others {
+		c, ok := ownedField(s, elem)
+		if c != nil {
+			nested[m] = c
+		}
+		// a manager owning some fields below val only makes those unknown
+		theirs = theirs || (ok && c == nil)
+	}
+	if !ours && (theirs || p.isAllocated(fp)) {
+		return unknownValue{}
+	}
+	// fields added on create are ours when admission adds them during our
+	// apply, so they are checked regardless of ownership
+	if p.defaulted != nil && manifest == nil && !p.defaulted(fp) {
+		return unknownValue{}
+	}
+	return p.value(val, manifest, child, nested, fp)
+}
+
+func (p *dryRunPlanner) isAllocated(fp []string) bool {
+	for _, a := range p.allocated {
+		if slices.Equal(a, fp) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedField returns the fields of s below the element elem selects, and
+// whether s contains that element at all.
+func ownedField(
+	s *fieldpath.Set,
+	elem func(*fieldpath.Set) (fieldpath.PathElement, bool),
+) (*fieldpath.Set, bool) {
+	if s == nil {
+		return nil, false
+	}
+	pe, ok := elem(s)
+	if !ok {
+		return nil, false
+	}
+	if c, ok := s.Children.Get(pe); ok {
+		return c, true
+	}
+	return nil, s.Members.Has(pe)
+}
+
+// conformToPlannedObject returns actual, the object read back after an apply,
+// in the shape of planned, an object planned from a dry run, which Terraform
+// requires it to have. Fields planned as unknown take their real value and
+// known fields are checked against the plan by Terraform. Fields the plan does
+// not have, such as annotations a controller added in the meantime, are left
+// for the next refresh.
+func conformToPlannedObject(
+	ctx context.Context,
+	planned types.Dynamic,
+	actual types.Dynamic,
+) (types.Dynamic, diag.Diagnostics) {
+	if planned.IsNull() || planned.IsUnknown() || actual.IsNull() || actual.IsUnknown() {
+		return actual, nil
+	}
+	actualMap, diags := dynamicToMap(ctx, actual)
+	if diags.HasError() {
+		return actual, diags
+	}
+	v, d := conformValue(ctx, planned.UnderlyingValue(), actualMap)
+	diags.Append(d...)
+	if diags.HasError() {
+		return actual, diags
+	}
+	return types.DynamicValue(v), diags
+}
+
+func conformValue(
+	ctx context.Context,
+	planned attr.Value,
+	actual any,
+) (attr.Value, diag.Diagnostics) {
+	if dv, ok := planned.(basetypes.DynamicValue); ok && !dv.IsNull() && !dv.IsUnknown() {
+		planned = dv.UnderlyingValue()
+	}
+	if planned == nil || planned.IsNull() || planned.IsUnknown() {
+		return decodeAny(ctx, actual)
+	}
+	switch pv := planned.(type) {
+	case basetypes.ObjectValue:
+		am, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		attrs := pv.Attributes()
+		vm := make(map[string]attr.Value, len(attrs))
+		tm := make(map[string]attr.Type, len(attrs))
+		for k, pa := range attrs {
+			v, diags := conformValue(ctx, pa, am[k])
+			if diags.HasError() {
+				return nil, diags
+			}
+			vm[k], tm[k] = v, v.Type(ctx)
+		}
+		return types.ObjectValue(tm, vm)
+	case basetypes.TupleValue:
+		al, ok := actual.([]any)
+		elems := pv.Elements()
+		if !ok || len(al) != len(elems) {
+			break
+		}
+		vl := make([]attr.Value, len(al))
+		tl := make([]attr.Type, len(al))
+		for i, pe := range elems {
+			v, diags := conformValue(ctx, pe, al[i])
+			if diags.HasError() {
+				return nil, diags
+			}
+			vl[i], tl[i] = v, v.Type(ctx)
+		}
+		return types.TupleValue(tl, vl)
+	}
+	return decodeAny(ctx, actual)
+}
@@ -0,0 +1,606 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPlanDryRunEnabled(t *testing.T) {
+	samples := map[string]struct {
+		resource types.Bool
+		provider bool
+		expected bool
+	}{
+		"unset":                    {resource: types.BoolNull(), expected: false},
+		"provider default":         {resource: types.BoolNull(), provider: true, expected: true},
+		"resource enables":         {resource: types.BoolValue(true), expected: true},
+		"resource overrides false": {resource: types.BoolValue(false), provider: true, expected: false},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			r := &manifestResource{providerData: &kubectlProviderData{planDryRun: s.provider}}
+			model := &manifestResourceModel{PlanDryRun: s.resource}
+			if got := r.planDryRunEnabled(model); got != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestFieldManagerSettings(t *testing.T) {
+	ctx := context.Background()
+
+	model := &manifestResourceModel{FieldManager: types.ObjectNull(fieldManagerBlockAttrTypes())}
+	name, force, err := fieldManagerSettings(ctx, model)
+	if err != nil || name != "Terraform" || force {
+		t.Fatalf("unexpected defaults: %q %t %v", name, force, err)
+	}
+
+	model.FieldManager = types.ObjectValueMust(fieldManagerBlockAttrTypes(), map[string]attr.Value{
+		"name":            types.StringValue("ci"),
+		"force_conflicts": types.BoolValue(true),
+		"migrate_from":    types.ListNull(types.StringType),
+	})
+	name, force, err = fieldManagerSettings(ctx, model)
+	if err != nil || name != "ci" || !force {
+		t.Fatalf("unexpected settings: %q %t %v", name, force, err)
+	}
+}
+
+// dryRunServiceResult is a Service as returned by a dry run of its creation:
+// the API server allocated a cluster IP and node port and defaulted the rest.
+const dryRunServiceResult = `{
+  "apiVersion": "v1",
+  "kind": "Service",
+  "metadata": {
+    "name": "web",
+    "namespace": "default",
+    "uid": "4f1c9a0e-dry-run",
+    "creationTimestamp": "2026-01-01T00:00:00Z",
+    "managedFields": [{
+      "manager": "Terraform",
+      "operation": "Apply",
+      "fieldsType": "FieldsV1",
+      "fieldsV1": {"f:spec": {
+        "f:type": {},
+        "f:ports": {"k:{\"port\":80,\"protocol\":\"TCP\"}": {".": {}, "f:port": {}}}
+      }}
+    }]
+  },
+  "spec": {
+    "type": "NodePort",
+    "clusterIP": "10.96.0.12",
+    "clusterIPs": ["10.96.0.12"],
+    "sessionAffinity": "None",
+    "ports": [{"port": 80, "protocol": "TCP", "targetPort": 80, "nodePort": 31234}]
+  },
+  "status": {"loadBalancer": {}}
+}`
+
+// dryRunPodResult is a Pod as returned by a dry run of its creation: the
+// ServiceAccount admission plugin added a randomly named token volume, and
+// the API server defaulted the rest.
+const dryRunPodResult = `{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {
+    "name": "app",
+    "namespace": "default",
+    "uid": "8e2d41c7-dry-run",
+    "creationTimestamp": "2026-01-01T00:00:00Z",
+    "managedFields": [{
+      "manager": "Terraform",
+      "operation": "Apply",
+      "fieldsType": "FieldsV1",
+      "fieldsV1": {"f:spec": {"f:containers": {
+        "k:{\"name\":\"app\"}": {".": {}, "f:name": {}, "f:image": {}}
+      }}}
+    }]
+  },
+  "spec": {
+    "containers": [{
+      "name": "app",
+      "image": "nginx:1.28",
+      "imagePullPolicy": "IfNotPresent",
+      "volumeMounts": [{
+        "name": "kube-api-access-x7k2p",
+        "readOnly": true,
+        "mountPath": "/var/run/secrets/kubernetes.io/serviceaccount"
+      }]
+    }],
+    "restartPolicy": "Always",
+    "serviceAccountName": "default",
+    "volumes": [{
+      "name": "kube-api-access-x7k2p",
+      "projected": {"sources": [{"serviceAccountToken": {"path": "token"}}]}
+    }]
+  },
+  "status": {"phase": "Pending"}
+}`
+
+// dryRunDeploymentResult is a Deployment as returned by a dry run of an
+// update, while an autoscaler owns its replicas.
+const dryRunDeploymentResult = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "web",
+    "generation": 4,
+    "resourceVersion": "1234",
+    "annotations": {"deployment.kubernetes.io/revision": "3", "team": "web"},
+    "managedFields": [
+      {
+        "manager": "Terraform",
+        "operation": "Apply",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {
+          "f:metadata": {"f:annotations": {"f:team": {}}},
+          "f:spec": {"f:template": {"f:spec": {"f:containers": {
+            "k:{\"name\":\"app\"}": {".": {}, "f:name": {}, "f:image": {}}
+          }}}}
+        }
+      },
+      {
+        "manager": "kube-controller-manager",
+        "operation": "Update",
+        "subresource": "scale",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:spec": {"f:replicas": {}}}
+      },
+      {
+        "manager": "kube-controller-manager",
+        "operation": "Update",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:metadata": {"f:annotations": {
+          "f:deployment.kubernetes.io/revision": {}
+        }}}
+      }
+    ]
+  },
+  "spec": {
+    "replicas": 5,
+    "revisionHistoryLimit": 10,
+    "template": {"spec": {"containers": [
+      {"name": "app", "image": "nginx:1.28", "imagePullPolicy": "IfNotPresent"}
+    ]}}
+  }
+}`
+
+func TestPlannedObjectFromDryRun(t *testing.T) {
+	unknown := unknownValue{}
+	samples := map[string]struct {
+		object   string
+		manager  string
+		create   *dryRunCreate
+		expected map[string]any
+	}{
+		"allocated fields are unknown": {
+			object:  dryRunServiceResult,
+			manager: "Terraform",
+			create: &dryRunCreate{
+				manifest: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Service",
+					"metadata":   map[string]any{"name": "web", "namespace": "default"},
+					"spec": map[string]any{
+						"type":  "NodePort",
+						"ports": []any{map[string]any{"port": float64(80)}},
+					},
+				},
+				defaulted: func(fp []string) bool {
+					return slices.Equal(fp, []string{"spec", "sessionAffinity"}) ||
+						slices.Equal(fp, []string{"spec", "ports", "*", "protocol"})
+				},
+			},
+			expected: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]any{
+					"name":              "web",
+					"namespace":         "default",
+					"uid":               unknown,
+					"resourceVersion":   unknown,
+					"creationTimestamp": unknown,
+					"managedFields":     unknown,
+				},
+				"spec": map[string]any{
+					"type":            "NodePort",
+					"clusterIP":       unknown,
+					"clusterIPs":      unknown,
+					"sessionAffinity": "None",
+					"ports": []any{map[string]any{
+						"port":       int64(80),
+						"protocol":   "TCP",
+						"targetPort": unknown,
+						"nodePort":   unknown,
+					}},
+				},
+				"status": unknown,
+			},
+		},
+		"fields added on create are unknown": {
+			object:  dryRunPodResult,
+			manager: "Terraform",
+			create: &dryRunCreate{
+				manifest: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata":   map[string]any{"name": "app", "namespace": "default"},
+					"spec": map[string]any{"containers": []any{
+						map[string]any{"name": "app", "image": "nginx:1.28"},
+					}},
+				},
+				defaulted: func(fp []string) bool {
+					return slices.Equal(fp, []string{"spec", "restartPolicy"})
+				},
+			},
+			expected: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name":              "app",
+					"namespace":         "default",
+					"uid":               unknown,
+					"resourceVersion":   unknown,
+					"creationTimestamp": unknown,
+					"managedFields":     unknown,
+				},
+				"spec": map[string]any{
+					"containers": []any{map[string]any{
+						"name":            "app",
+						"image":           "nginx:1.28",
+						"imagePullPolicy": unknown,
+						"volumeMounts":    unknown,
+					}},
+					"restartPolicy":      "Always",
+					"serviceAccountName": unknown,
+					"volumes":            unknown,
+				},
+				"status": unknown,
+			},
+		},
+		"fields owned by other managers are unknown": {
+			object:  dryRunDeploymentResult,
+			manager: "Terraform",
+			expected: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]any{
+					"name":              "web",
+					"generation":        unknown,
+					"uid":               unknown,
+					"resourceVersion":   unknown,
+					"creationTimestamp": unknown,
+					"managedFields":     unknown,
+					"annotations": map[string]any{
+						"deployment.kubernetes.io/revision": unknown,
+						"team":                              "web",
+					},
+				},
+				"spec": map[string]any{
+					"replicas":             unknown,
+					"revisionHistoryLimit": int64(10),
+					"template": map[string]any{"spec": map[string]any{"containers": []any{
+						map[string]any{
+							"name":            "app",
+							"image":           "nginx:1.28",
+							"imagePullPolicy": "IfNotPresent",
+						},
+					}}},
+				},
+			},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			if err := u.UnmarshalJSON([]byte(s.object)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := plannedObjectFromDryRun(u.Object, s.manager, "", s.create)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, s.expected) {
+				t.Fatalf("unexpected planned object:\n got: %v\nwant: %v", got, s.expected)
+			}
+		})
+	}
+}
+
+func TestPlannedObjectFromDryRunOwnedAllocatedField(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON([]byte(dryRunServiceResult)); err != nil {
+		t.Fatal(err)
+	}
+	fields := u.GetManagedFields()
+	fields[0].FieldsV1.Raw = []byte(`{"f:spec": {"f:clusterIP": {}}}`)
+	u.SetManagedFields(fields)
+
+	got, err := plannedObjectFromDryRun(u.Object, "Terraform", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec, _ := got["spec"].(map[string]any)
+	if spec["clusterIP"] != "10.96.0.12" {
+		t.Fatalf("expected a cluster IP set by the manifest to be known, got %v", spec["clusterIP"])
+	}
+	if _, ok := spec["clusterIPs"].(unknownValue); !ok {
+		t.Fatalf("expected clusterIPs to be unknown, got %v", spec["clusterIPs"])
+	}
+}
+
+func TestConformToPlannedObject(t *testing.T) {
+	ctx := context.Background()
+	planned, d := mapToDynamic(ctx, map[string]any{
+		"metadata": map[string]any{
+			"name":            "web",
+			"resourceVersion": unknownValue{},
+			"annotations":     map[string]any{"team": "web"},
+		},
+		"spec": map[string]any{
+			"clusterIP": unknownValue{},
+			"ports":     []any{map[string]any{"port": float64(80), "nodePort": unknownValue{}}},
+		},
+		"status": unknownValue{},
+	})
+	if d.HasError() {
+		t.Fatalf("failed to build planned object: %v", d)
+	}
+	if v, err := planned.ToTerraformValue(ctx); err != nil || v.IsFullyKnown() {
+		t.Fatalf("expected a partially unknown planned object, got %v, %v", v, err)
+	}
+
+	actual, d := mapToDynamic(ctx, map[string]any{
+		"metadata": map[string]any{
+			"name":            "web",
+			"uid":             "4f1c9a0e",
+			"resourceVersion": "1235",
+			"annotations":     map[string]any{"team": "web", "added": "later"},
+		},
+		"spec": map[string]any{
+			"clusterIP": "10.96.0.40",
+			"ports":     []any{map[string]any{"port": float64(80), "nodePort": float64(30001)}},
+		},
+		"status": map[string]any{"loadBalancer": map[string]any{}},
+	})
+	if d.HasError() {
+		t.Fatalf("failed to build actual object: %v", d)
+	}
+
+	got, d := conformToPlannedObject(ctx, planned, actual)
+	if d.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", d)
+	}
+	gotMap, _ := dynamicToMap(ctx, got)
+	expected := map[string]any{
+		"metadata": map[string]any{
+			"name":            "web",
+			"resourceVersion": "1235",
+			"annotations":     map[string]any{"team": "web"},
+		},
+		"spec": map[string]any{
+			"clusterIP": "10.96.0.40",
+			"ports":     []any{map[string]any{"port": float64(80), "nodePort": float64(30001)}},
+		},
+		"status": map[string]any{"loadBalancer": map[string]any{}},
+	}
+	if !reflect.DeepEqual(gotMap, expected) {
+		t.Fatalf("unexpected object:\n got: %v\nwant: %v", gotMap, expected)
+	}
+
+	if got, _ := conformToPlannedObject(ctx, types.DynamicUnknown(), actual); !got.Equal(actual) {
+		t.Fatal("expected the object to be kept when none was planned")
+	}
+}
+
+// dryRunServiceSpecV3 declares the defaults of the fields of a Service the
+// dry-run tests use.
+const dryRunServiceSpecV3 = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1"},
+  "paths": {},
+  "components": {"schemas": {"io.k8s.api.core.v1.Service": {
+    "type": "object",
+    "x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Service"}],
+    "properties": {"spec": {"type": "object", "properties": {
+      "type": {"type": "string"},
+      "sessionAffinity": {"type": "string", "default": "None"},
+      "ports": {"type": "array", "items": {"type": "object", "properties": {
+        "port": {"type": "integer"},
+        "protocol": {"type": "string", "default": "TCP"}
+      }}}
+    }}}
+  }}}
+}`
+
+// dryRunTestResource returns a manifest resource whose cluster is a fake
+// dynamic client answering patches with react, and whose Service schema is
+// dryRunServiceSpecV3.
+func dryRunTestResource(
+	t *testing.T,
+	react k8stesting.ReactionFunc,
+) *manifestResource {
+	t.Helper()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(k8sschema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("patch", "services", react)
+
+	foundry, err := api.NewFoundryFromSpecV3([]byte(dryRunServiceSpecV3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	_, _ = p.restMapper.Get(func() (meta.RESTMapper, error) { return mapper, nil })
+	_, _ = p.dynamicClient.Get(func() (dynamic.Interface, error) { return client, nil })
+	_, _ = p.localSchemas.Get(func() (*localSchemas, error) {
+		return &localSchemas{foundries: []api.Foundry{foundry}}, nil
+	})
+	return &manifestResource{providerData: p}
+}
+
+func TestDryRunObject(t *testing.T) {
+	ctx := context.Background()
+	manifest := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "web", "namespace": "default"},
+		"spec": map[string]any{
+			"type":  "NodePort",
+			"ports": []any{map[string]any{"port": float64(80)}},
+		},
+	}
+	plan := &manifestResourceModel{FieldManager: types.ObjectNull(fieldManagerBlockAttrTypes())}
+
+	t.Run("dry run result", func(t *testing.T) {
+		var opts meta_v1.PatchOptions
+		var patchType k8stypes.PatchType
+		r := dryRunTestResource(t, func(a k8stesting.Action) (bool, runtime.Object, error) {
+			pa, _ := a.(k8stesting.PatchActionImpl)
+			opts, patchType = pa.PatchOptions, pa.PatchType
+			u := &unstructured.Unstructured{}
+			return true, u, u.UnmarshalJSON([]byte(dryRunServiceResult))
+		})
+
+		object, diags := r.dryRunObject(ctx, plan, manifest, nil)
+		if diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+		if patchType != k8stypes.ApplyPatchType || opts.FieldManager != "Terraform" ||
+			!reflect.DeepEqual(opts.DryRun, []string{meta_v1.DryRunAll}) {
+			t.Fatalf("expected a server-side apply dry run, got %s %+v", patchType, opts)
+		}
+		v, err := object.ToTerraformValue(ctx)
+		if err != nil || object.IsUnknown() || v.IsFullyKnown() {
+			t.Fatalf("expected a partially known object, got %v, %v", object, err)
+		}
+		objMap, _ := dynamicToMap(ctx, object)
+		spec, _ := objMap["spec"].(map[string]any)
+		if spec["sessionAffinity"] != "None" || spec["clusterIP"] != nil {
+			t.Fatalf("expected defaults to be known and the cluster IP unknown, got %v", spec)
+		}
+	})
+
+	t.Run("missing namespace", func(t *testing.T) {
+		r := dryRunTestResource(t, func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8s_errors.NewNotFound(
+				k8sschema.GroupResource{Resource: "namespaces"}, "default")
+		})
+		object, diags := r.dryRunObject(ctx, plan, manifest, nil)
+		if diags.HasError() || !object.IsUnknown() {
+			t.Fatalf("expected an unknown object without errors, got %v, %v", object, diags)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		r := dryRunTestResource(t, func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, k8s_errors.NewInvalid(
+				k8sschema.GroupKind{Kind: "Service"}, "web", field.ErrorList{
+					field.Invalid(field.NewPath("spec", "type"), "NodePort", "not allowed"),
+				})
+		})
+		object, diags := r.dryRunObject(ctx, plan, manifest, nil)
+		if !object.IsUnknown() || !diags.HasError() {
+			t.Fatalf("expected errors, got %v, %v", object, diags)
+		}
+		withPath, ok := diags[0].(interface{ Path() path.Path })
+		if !ok || !withPath.Path().Equal(path.Root("manifest").AtName("spec").AtName("type")) {
+			t.Fatalf("expected the first diagnostic on manifest.spec.type, got %v", diags[0])
+		}
+	})
+}
+
+func TestImmutableFieldChanged(t *testing.T) {
+	ctx := context.Background()
+	manifest, diags := mapToDynamic(ctx, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]any{"name": "web", "namespace": "default"},
+		"spec":       map[string]any{"clusterIP": "10.0.0.2"},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	config := tfsdk.Config{
+		Schema: schema.Schema{Attributes: map[string]schema.Attribute{
+			"manifest_wo": schema.DynamicAttribute{Optional: true},
+		}},
+		Raw: tftypes.NewValue(
+			tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"manifest_wo": tftypes.DynamicPseudoType,
+			}},
+			map[string]tftypes.Value{
+				"manifest_wo": tftypes.NewValue(tftypes.DynamicPseudoType, nil),
+			},
+		),
+	}
+	immutable := k8s_errors.NewInvalid(k8sschema.GroupKind{Kind: "Service"}, "web", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.2", "field is immutable"),
+	})
+	rejected := k8s_errors.NewInvalid(k8sschema.GroupKind{Kind: "Service"}, "web", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "clusterIP"), "10.0.0.2", "not in the service range"),
+	})
+
+	samples := map[string]struct {
+		recreate types.Bool
+		err      error
+		expected bool
+	}{
+		"immutable field":   {recreate: types.BoolValue(true), err: immutable, expected: true},
+		"other rejection":   {recreate: types.BoolValue(true), err: rejected},
+		"accepted":          {recreate: types.BoolValue(true)},
+		"disabled":          {recreate: types.BoolValue(false), err: immutable},
+		"unset":             {recreate: types.BoolNull(), err: immutable},
+		"not yet evaluated": {recreate: types.BoolUnknown(), err: immutable},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			r := dryRunTestResource(t, func(k8stesting.Action) (bool, runtime.Object, error) {
+				if s.err != nil {
+					return true, nil, s.err
+				}
+				return true, &unstructured.Unstructured{Object: map[string]any{}}, nil
+			})
+			r.providerData.configFullyKnown = true
+			plan := &manifestResourceModel{
+				Manifest:                 manifest,
+				FieldManager:             types.ObjectNull(fieldManagerBlockAttrTypes()),
+				RecreateOnImmutableError: s.recreate,
+			}
+			replace, diags := r.immutableFieldChanged(ctx, config, plan)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if replace != s.expected {
+				t.Fatalf("expected %t, got %t", s.expected, replace)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	k8s_errors "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// compileExpression compiles v, the value of an expression attribute. It
+// returns nil when the attribute is not set.
+func compileExpression(v types.String) (*api.Expression, error) {
+	if v.IsNull() || v.IsUnknown() || v.ValueString() == "" {
+		return nil, nil
+	}
+	return api.CompileExpression(v.ValueString())
+}
+
+// validateExpression reports an error on p when v is not a valid expression.
+func validateExpression(p path.Path, v types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if _, err := compileExpression(v); err != nil {
+		diags.AddAttributeError(p, "Invalid expression", err.Error())
+	}
+	return diags
+}
+
+// modelExpressions compiles wait.expression and error.expression of model.
+func modelExpressions(
+	ctx context.Context,
+	model *manifestResourceModel,
+) (*api.Expression, *api.Expression, error) {
+	var waitExpr, errorExpr *api.Expression
+	if !model.Wait.IsNull() && !model.Wait.IsUnknown() {
+		var wait waitModel
+		if d := model.Wait.As(ctx, &wait, basetypes.ObjectAsOptions{}); !d.HasError() {
+			var err error
+			if waitExpr, err = compileExpression(wait.Expression); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if !model.Error.IsNull() && !model.Error.IsUnknown() {
+		var errOn errorModel
+		if d := model.Error.As(ctx, &errOn, basetypes.ObjectAsOptions{}); !d.HasError() {
+			var err error
+			if errorExpr, err = compileExpression(errOn.Expression); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return waitExpr, errorExpr, nil
+}
+
+// setExpressionsOldSelf reads the named object from rs, before it is applied,
+// as the oldSelf of those of exprs that refer to it.
+func setExpressionsOldSelf(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	name string,
+	exprs ...*api.Expression,
+) error {
+	usesOldSelf := false
+	for _, e := range exprs {
+		usesOldSelf = usesOldSelf || e.UsesOldSelf()
+	}
+	if !usesOldSelf {
+		return nil
+	}
+
+	old, err := rs.Get(ctx, name, meta_v1.GetOptions{})
+	if k8s_errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read resource for oldSelf: %w", err)
+	}
+	for _, e := range exprs {
+		if e != nil {
+			e.OldSelf = old
+		}
+	}
+	return nil
+}
+
+// matchErrorExpression returns an error when expr, the error expression,
+// holds for res, the named resource.
+func matchErrorExpression(
+	res *meta_v1_unstruct.Unstructured,
+	name string,
+	expr *api.Expression,
+) error {
+	if expr == nil {
+		return nil
+	}
+	matched, err := expr.Eval(res)
+	if err != nil || !matched {
+		// Can't check, don't fail
+		return nil //nolint:nilerr
+	}
+	return &MatchingConditionError{
+		Msg: fmt.Sprintf("error condition met for %s: expression %s holds", name, expr.Expr),
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func expressionTestJob(generation int64) *meta_v1_unstruct.Unstructured {
+	uo := &meta_v1_unstruct.Unstructured{}
+	uo.SetAPIVersion("batch/v1")
+	uo.SetKind("Job")
+	uo.SetNamespace("default")
+	uo.SetName("migrate")
+	uo.SetGeneration(generation)
+	return uo
+}
+
+func TestSetExpressionsOldSelf(t *testing.T) {
+	ctx := context.Background()
+	gvr := k8sschema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), expressionTestJob(4))
+	rs := client.Resource(gvr).Namespace("default")
+
+	transition, err := api.CompileExpression(
+		"self.metadata.generation > oldSelf.metadata.generation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := api.CompileExpression("self.metadata.generation > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setExpressionsOldSelf(ctx, rs, "migrate", transition, plain, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transition.OldSelf == nil || transition.OldSelf.GetGeneration() != 4 {
+		t.Fatalf("expected oldSelf to be the object before the apply, got %v", transition.OldSelf)
+	}
+
+	created, err := api.CompileExpression("oldSelf == null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setExpressionsOldSelf(ctx, rs, "other", created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.OldSelf != nil {
+		t.Fatalf("expected no oldSelf for an object that does not exist yet")
+	}
+}
+
+func TestMatchErrorExpression(t *testing.T) {
+	expr, err := api.CompileExpression("self.metadata.generation > 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mce *MatchingConditionError
+	err = matchErrorConditions(expressionTestJob(4), "migrate", nil, nil, expr)
+	if !errors.As(err, &mce) {
+		t.Fatalf("expected a MatchingConditionError, got %v", err)
+	}
+	if err := matchErrorConditions(expressionTestJob(2), "migrate", nil, nil, expr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := matchErrorExpression(expressionTestJob(4), "migrate", nil); err != nil {
+		t.Fatalf("expected no error without an expression, got %v", err)
+	}
+}
+
+func TestValidateExpression(t *testing.T) {
+	p := path.Root("wait").AtName("expression")
+	if diags := validateExpression(p, types.StringValue("self.status.ready")); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if diags := validateExpression(p, types.StringUnknown()); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for an unknown expression: %v", diags)
+	}
+	if diags := validateExpression(p, types.StringValue("self.status.")); !diags.HasError() {
+		t.Fatal("expected an error for an invalid expression")
+	}
+}
+
+func TestWaitWithErrorCheckTimeout(t *testing.T) {
+	gvr := k8sschema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), expressionTestJob(1))
+	client.PrependWatchReactor("jobs",
+		func(a k8stesting.Action) (bool, watch.Interface, error) {
+			return true, watch.NewFake(), nil
+		})
+	rs := client.Resource(gvr).Namespace("default")
+
+	expr, err := api.CompileExpression("self.status.succeeded > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter := &api.ExpressionWaiter{
+		Resource:     rs,
+		ResourceName: "migrate",
+		Expression:   expr,
+		Logger:       hclog.NewNullLogger(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	r := &manifestResource{}
+	err = r.waitWithErrorCheck(ctx, rs, waiter, "migrate", nil, nil, nil)
+	var we api.WaiterError
+	if !errors.As(err, &we) || we.Reason != "expression" {
+		t.Fatalf("expected a WaiterError for the expression, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed to evaluate expression") {
+		t.Fatalf("expected the last evaluation error, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// generatedNameKey is the private state key holding the name the API server
+// assigned to an object created from metadata.generateName.
+const generatedNameKey = "generated_name"
+
+// privateStateGetter reads the private state of a request.
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// usesGenerateName reports whether manifest leaves the name of the object to
+// the API server, setting metadata.generateName but no metadata.name.
+func usesGenerateName(ctx context.Context, manifest types.Dynamic) bool {
+	name, _ := extractManifestMetadataField(ctx, manifest, "name")
+	generateName, _ := extractManifestMetadataField(ctx, manifest, "generateName")
+	return name == "" && generateName != ""
+}
+
+// withGeneratedName returns manifest with metadata.name set to the name
+// stored in private, or manifest itself when no name was assigned.
+func withGeneratedName(
+	ctx context.Context,
+	private privateStateGetter,
+	manifest types.Dynamic,
+) (types.Dynamic, diag.Diagnostics) {
+	name, diags := private.GetKey(ctx, generatedNameKey)
+	if diags.HasError() || len(name) == 0 || !usesGenerateName(ctx, manifest) {
+		return manifest, diags
+	}
+	named, d := setManifestName(ctx, manifest, string(name))
+	diags.Append(d...)
+	return named, diags
+}
+
+// objectName returns the name of the object model describes, which for
+// objects created from metadata.generateName is only found in object.
+func objectName(ctx context.Context, model *manifestResourceModel) string {
+	if name, _ := extractManifestMetadataField(ctx, model.Manifest, "name"); name != "" {
+		return name
+	}
+	if model.Object.IsNull() || model.Object.IsUnknown() {
+		return ""
+	}
+	name, _ := extractManifestMetadataField(ctx, model.Object, "name")
+	return name
+}
+
+// setManifestName returns manifest with metadata.name set to name.
+func setManifestName(
+	ctx context.Context,
+	manifest types.Dynamic,
+	name string,
+) (types.Dynamic, diag.Diagnostics) {
+	manifestMap, diags := dynamicToMap(ctx, manifest)
+	if diags.HasError() || manifestMap == nil {
+		return manifest, diags
+	}
+	uo := meta_v1_unstruct.Unstructured{Object: manifestMap}
+	uo.SetName(name)
+	named, d := mapToDynamic(ctx, uo.Object)
+	diags.Append(d...)
+	return named, diags
+}
+
+// createObject creates the object model describes, with manifest_wo merged
+// in, and returns its name, which for objects named by metadata.generateName
+// is the name the API server generated. The object is created with a POST, as
+// a server-side apply needs a name and adopts objects that already exist. The
+// fields it sets are then handed over to the apply operations of the field
+// manager, so that later applies can remove them.
+func (r *manifestResource) createObject(
+	ctx context.Context,
+	model *manifestResourceModel,
+	manifestWoMap map[string]any,
+) (string, error) {
+	manifestMap, diags := dynamicToMap(ctx, model.Manifest)
+	if diags.HasError() {
+		return "", fmt.Errorf("failed to convert manifest to map: %v", diags)
+	}
+	deepMergeMaps(manifestMap, manifestWoMap)
+	uo := &meta_v1_unstruct.Unstructured{Object: api.MapRemoveNulls(manifestMap)}
+
+	manager, _, err := fieldManagerSettings(ctx, model)
+	if err != nil {
+		return "", err
+	}
+	setOwnerAnnotation(uo, createPolicy(model), manager)
+
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, yaml.NewFromUnstructured(uo))
+	if restClient.Error != nil {
+		return "", fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
+	}
+	return createWithPost(ctx, restClient.ResourceInterface, uo, manager)
+}
+
+// createWithPost creates uo in rs on behalf of manager and returns its name.
+// The name is returned even when handing over the fields fails, as the object
+// exists by then.
+func createWithPost(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	uo *meta_v1_unstruct.Unstructured,
+	manager string,
+) (string, error) {
+	desc := fmt.Sprintf("%s %q", uo.GetKind(), uo.GetName())
+	if uo.GetName() == "" {
+		desc = fmt.Sprintf("%s from generateName %q", uo.GetKind(), uo.GetGenerateName())
+	}
+	result, err := rs.Create(ctx, uo, meta_v1.CreateOptions{FieldManager: manager})
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", desc, err)
+	}
+	name := result.GetName()
+	log.Printf("[DEBUG] Created resource %s/%s", result.GetKind(), name)
+
+	return name, migrateFieldManagers(ctx, rs, name, []string{manager}, manager)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func TestCreateWithPost(t *testing.T) {
+	ctx := context.Background()
+	gvr := k8sschema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("create", "jobs",
+		func(a k8stesting.Action) (bool, runtime.Object, error) {
+			uo := a.(k8stesting.CreateAction).GetObject().(*meta_v1_unstruct.Unstructured)
+			uo.SetName(uo.GetGenerateName() + "x7k2p")
+			return false, nil, nil
+		})
+	rs := client.Resource(gvr).Namespace("default")
+
+	uo := &meta_v1_unstruct.Unstructured{}
+	uo.SetAPIVersion("batch/v1")
+	uo.SetKind("Job")
+	uo.SetNamespace("default")
+	uo.SetGenerateName("migrate-")
+
+	name, err := createWithPost(ctx, rs, uo, "Terraform")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "migrate-x7k2p" {
+		t.Fatalf("expected the generated name, got %q", name)
+	}
+	if _, err := rs.Get(ctx, name, meta_v1.GetOptions{}); err != nil {
+		t.Fatalf("expected the object to exist: %v", err)
+	}
+}
+
+func TestWithGeneratedName(t *testing.T) {
+	ctx := context.Background()
+	manifest := func(metadata map[string]any) types.Dynamic {
+		d, diags := mapToDynamic(ctx, map[string]any{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata":   metadata,
+		})
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return d
+	}
+	assigned := testPrivateState{generatedNameKey: []byte("migrate-x7k2p")}
+
+	samples := map[string]struct {
+		manifest types.Dynamic
+		private  testPrivateState
+		expected string
+	}{
+		"generated": {
+			manifest: manifest(map[string]any{"generateName": "migrate-"}),
+			private:  assigned,
+			expected: "migrate-x7k2p",
+		},
+		"not created yet": {
+			manifest: manifest(map[string]any{"generateName": "migrate-"}),
+			private:  testPrivateState{},
+		},
+		"named": {
+			manifest: manifest(map[string]any{"name": "migrate"}),
+			private:  assigned,
+			expected: "migrate",
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			named, diags := withGeneratedName(ctx, s.private, s.manifest)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			got, _ := extractManifestMetadataField(ctx, named, "name")
+			if got != s.expected {
+				t.Fatalf("expected name %q, got %q", s.expected, got)
+			}
+			model := &manifestResourceModel{Manifest: s.manifest, Object: named}
+			if got := objectName(ctx, model); got != s.expected {
+				t.Fatalf("expected object name %q, got %q", s.expected, got)
+			}
+		})
+	}
+}
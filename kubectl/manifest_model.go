@@ -112,6 +112,13 @@ func setStateFromUnstructured(
 		model.Object = objectDynamic
 	}
 
+	// Drift against the prior manifest is computed by Read.
+	drift, d := driftToList(ctx, nil)
+	diags.Append(d...)
+	if !diags.HasError() {
+		model.Drift = drift
+	}
+
 	return diags
 }
 
@@ -177,29 +184,34 @@ func extractManifestMetadataField(
 // This prevents perpetual diffs caused by server-generated fields.
 // It recursively reconciles nested maps so that server-defaulted fields
 // at any depth are excluded (e.g., spec.template.spec.dnsPolicy).
+//
+// When owners is non-nil, only fields still owned by our field manager take
+// their API value; the fields another manager has taken over keep their prior
+// value. The fields whose API value differs from prior are returned as drift.
 func reconcileDynamicWithPrior(
 	ctx context.Context,
 	prior types.Dynamic,
 	apiResult types.Dynamic,
-) types.Dynamic {
+	owners *fieldOwnership,
+) (types.Dynamic, []fieldDrift) {
 	if prior.IsNull() || prior.IsUnknown() {
-		return prior
+		return prior, nil
 	}
 	if apiResult.IsNull() || apiResult.IsUnknown() {
-		return prior
+		return prior, nil
 	}
 
 	priorMap, d := dynamicToMap(ctx, prior)
 	if d.HasError() || priorMap == nil {
-		return prior
+		return prior, nil
 	}
 	apiMap, d := dynamicToMap(ctx, apiResult)
 	if d.HasError() || apiMap == nil {
-		return prior
+		return prior, nil
 	}
 
 	// Deep reconcile: keep only attributes from prior, recursing into nested maps
-	result := deepReconcileMaps(priorMap, apiMap)
+	result, drift := owners.reconcile(priorMap, apiMap)
 
 	// Preserve the container types (Object vs Map, Tuple vs List) from the
 	// prior value so the state's Dynamic matches the plan's Dynamic on
@@ -209,9 +221,9 @@ func reconcileDynamicWithPrior(
 	// and "(known after apply)" churn on object/status.
 	dynResult, d := mapToDynamicPreservingTypes(ctx, result, prior)
 	if d.HasError() {
-		return prior
+		return prior, nil
 	}
-	return dynResult
+	return dynResult, drift
 }
 
 // deepReconcileMaps recursively reconciles two maps, keeping only keys from
@@ -226,35 +238,36 @@ func deepReconcileMaps(prior, api map[string]any) map[string]any {
 			result[k] = priorVal
 			continue
 		}
+		result[k] = reconcileValue(priorVal, apiVal)
+	}
+	return result
+}
 
-		// Recurse into nested maps
-		priorMap, priorIsMap := priorVal.(map[string]any)
-		apiMap, apiIsMap := apiVal.(map[string]any)
-		if priorIsMap && apiIsMap {
-			result[k] = deepReconcileMaps(priorMap, apiMap)
-			continue
-		}
+// reconcileValue reconciles a single prior value with its API counterpart.
+func reconcileValue(priorVal, apiVal any) any {
+	// Recurse into nested maps
+	priorMap, priorIsMap := priorVal.(map[string]any)
+	apiMap, apiIsMap := apiVal.(map[string]any)
+	if priorIsMap && apiIsMap {
+		return deepReconcileMaps(priorMap, apiMap)
+	}
 
-		// Recurse into arrays of maps (e.g., containers, volumes)
-		priorSlice, priorIsSlice := priorVal.([]any)
-		apiSlice, apiIsSlice := apiVal.([]any)
-		if priorIsSlice && apiIsSlice {
-			result[k] = deepReconcileSlices(priorSlice, apiSlice)
-			continue
-		}
+	// Recurse into arrays of maps (e.g., containers, volumes)
+	priorSlice, priorIsSlice := priorVal.([]any)
+	apiSlice, apiIsSlice := apiVal.([]any)
+	if priorIsSlice && apiIsSlice {
+		return deepReconcileSlices(priorSlice, apiSlice)
+	}
 
-		// Scalar or type mismatch: use API value, but don't overwrite a
-		// configured non-null prior value with a null from the remote.
-		// The API server (via OpenAPI schema filling + morph.UnknownToNull)
-		// sets absent optional fields to null; we should not let those nulls
-		// erase fields the user explicitly configured.
-		if apiVal == nil && priorVal != nil {
-			result[k] = priorVal
-		} else {
-			result[k] = apiVal
-		}
+	// Scalar or type mismatch: use API value, but don't overwrite a
+	// configured non-null prior value with a null from the remote.
+	// The API server (via OpenAPI schema filling + morph.UnknownToNull)
+	// sets absent optional fields to null; we should not let those nulls
+	// erase fields the user explicitly configured.
+	if apiVal == nil && priorVal != nil {
+		return priorVal
 	}
-	return result
+	return apiVal
 }
 
 // deepReconcileSlices reconciles two slices element-by-element.
@@ -67,28 +67,44 @@ type manifestResource struct {
 // (apiVersion, kind, metadata, spec, data, etc.) as a single Dynamic value,
 // aligning with the upstream hashicorp/terraform-provider-kubernetes pattern.
 type manifestResourceModel struct {
-	ID           types.String   `tfsdk:"id"`
-	Manifest     types.Dynamic  `tfsdk:"manifest"`
-	ManifestWo   types.Dynamic  `tfsdk:"manifest_wo"`
-	Status       types.Dynamic  `tfsdk:"status"`
-	Object       types.Dynamic  `tfsdk:"object"`
-	Fields       types.Object   `tfsdk:"fields"`
-	Delete       types.Object   `tfsdk:"delete"`
-	Wait         types.Object   `tfsdk:"wait"`
-	Error        types.Object   `tfsdk:"error"`
-	FieldManager types.Object   `tfsdk:"field_manager"`
-	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+	ID                       types.String   `tfsdk:"id"`
+	Manifest                 types.Dynamic  `tfsdk:"manifest"`
+	ManifestWo               types.Dynamic  `tfsdk:"manifest_wo"`
+	Status                   types.Dynamic  `tfsdk:"status"`
+	Object                   types.Dynamic  `tfsdk:"object"`
+	Drift                    types.List     `tfsdk:"drift"`
+	Fields                   types.Object   `tfsdk:"fields"`
+	Delete                   types.Object   `tfsdk:"delete"`
+	Wait                     types.Object   `tfsdk:"wait"`
+	Error                    types.Object   `tfsdk:"error"`
+	FieldManager             types.Object   `tfsdk:"field_manager"`
+	PlanDryRun               types.Bool     `tfsdk:"plan_dry_run"`
+	CreatePolicy             types.String   `tfsdk:"create_policy"`
+	RecreateOnImmutableError types.Bool     `tfsdk:"recreate_on_immutable_error"`
+	Subresource              types.String   `tfsdk:"subresource"`
+	OnUIDChange              types.String   `tfsdk:"on_uid_change"`
+	Timeouts                 timeouts.Value `tfsdk:"timeouts"`
 }
 
 // waitModel describes the wait attribute.
 type waitModel struct {
-	Rollout    types.Bool `tfsdk:"rollout"`
-	Fields     types.List `tfsdk:"fields"`
-	Conditions types.List `tfsdk:"conditions"`
+	Rollout    types.Bool   `tfsdk:"rollout"`
+	Ready      types.Bool   `tfsdk:"ready"`
+	Fields     types.List   `tfsdk:"fields"`
+	Conditions types.List   `tfsdk:"conditions"`
+	Expression types.String `tfsdk:"expression"`
 }
 
 // waitFieldModel describes a field matcher in the wait block.
 type waitFieldModel struct {
+	Key        types.String `tfsdk:"key"`
+	Value      types.String `tfsdk:"value"`
+	ValueType  types.String `tfsdk:"value_type"`
+	Quantifier types.String `tfsdk:"quantifier"`
+}
+
+// errorFieldModel describes a field matcher in the error block.
+type errorFieldModel struct {
 	Key       types.String `tfsdk:"key"`
 	Value     types.String `tfsdk:"value"`
 	ValueType types.String `tfsdk:"value_type"`
@@ -104,8 +120,9 @@ type waitConditionModel struct {
 // Error conditions are checked continuously while waiting for success conditions.
 // If any error condition matches, the apply fails immediately.
 type errorModel struct {
-	Fields     types.List `tfsdk:"fields"`
-	Conditions types.List `tfsdk:"conditions"`
+	Fields     types.List   `tfsdk:"fields"`
+	Conditions types.List   `tfsdk:"conditions"`
+	Expression types.String `tfsdk:"expression"`
 }
 
 // fieldsModel describes the fields attribute.
@@ -124,6 +141,13 @@ type deleteModel struct {
 type fieldManagerModel struct {
 	Name           types.String `tfsdk:"name"`
 	ForceConflicts types.Bool   `tfsdk:"force_conflicts"`
+	MigrateFrom    types.List   `tfsdk:"migrate_from"`
+}
+
+// driftModel describes an entry of the drift attribute.
+type driftModel struct {
+	Path    types.String `tfsdk:"path"`
+	Manager types.String `tfsdk:"manager"`
 }
 
 // manifestIdentityModel describes the resource identity.
@@ -132,17 +156,20 @@ type manifestIdentityModel struct {
 	Kind       types.String `tfsdk:"kind"`
 	Name       types.String `tfsdk:"name"`
 	Namespace  types.String `tfsdk:"namespace"`
+	UID        types.String `tfsdk:"uid"`
 }
 
 // waitBlockAttrTypes returns the attribute types map for the wait attribute.
 func waitBlockAttrTypes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"rollout": types.BoolType,
+		"ready":   types.BoolType,
 		"fields": types.ListType{ElemType: types.ObjectType{
 			AttrTypes: map[string]attr.Type{
 				"key":        types.StringType,
 				"value":      types.StringType,
 				"value_type": types.StringType,
+				"quantifier": types.StringType,
 			},
 		}},
 		"conditions": types.ListType{ElemType: types.ObjectType{
@@ -151,6 +178,7 @@ func waitBlockAttrTypes() map[string]attr.Type {
 				"status": types.StringType,
 			},
 		}},
+		"expression": types.StringType,
 	}
 }
 
@@ -170,6 +198,7 @@ func errorAttrTypes() map[string]attr.Type {
 				"status": types.StringType,
 			},
 		}},
+		"expression": types.StringType,
 	}
 }
 
@@ -189,16 +218,30 @@ func deleteAttrTypes() map[string]attr.Type {
 	}
 }
 
+// driftAttrTypes returns the attribute types map for entries of the drift attribute.
+func driftAttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"path":    types.StringType,
+		"manager": types.StringType,
+	}
+}
+
 // fieldManagerBlockAttrTypes returns the attribute types map for the field_manager block.
 func fieldManagerBlockAttrTypes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"name":            types.StringType,
 		"force_conflicts": types.BoolType,
+		"migrate_from":    types.ListType{ElemType: types.StringType},
 	}
 }
 
-// buildIdentityModel creates an identity model from a manifest resource model.
-func buildIdentityModel(ctx context.Context, model *manifestResourceModel) manifestIdentityModel {
+// buildIdentityModel creates an identity model from a manifest resource model
+// and the UID of the object it manages.
+func buildIdentityModel(
+	ctx context.Context,
+	model *manifestResourceModel,
+	uid string,
+) manifestIdentityModel {
 	identity := manifestIdentityModel{}
 
 	apiVersion, _ := extractManifestField(ctx, model.Manifest, "apiVersion")
@@ -206,8 +249,7 @@ func buildIdentityModel(ctx context.Context, model *manifestResourceModel) manif
 	identity.APIVersion = types.StringValue(fmt.Sprintf("%v", apiVersion))
 	identity.Kind = types.StringValue(fmt.Sprintf("%v", kind))
 
-	name, _ := extractManifestMetadataField(ctx, model.Manifest, "name")
-	if name != "" {
+	if name := objectName(ctx, model); name != "" {
 		identity.Name = types.StringValue(name)
 	}
 	namespace, _ := extractManifestMetadataField(ctx, model.Manifest, "namespace")
@@ -216,6 +258,10 @@ func buildIdentityModel(ctx context.Context, model *manifestResourceModel) manif
 	} else {
 		identity.Namespace = types.StringNull()
 	}
+	identity.UID = types.StringNull()
+	if uid != "" {
+		identity.UID = types.StringValue(uid)
+	}
 
 	return identity
 }
@@ -225,13 +271,14 @@ func setResponseIdentity(
 	ctx context.Context,
 	identity *tfsdk.ResourceIdentity,
 	model *manifestResourceModel,
+	uid string,
 	diagnostics *diag.Diagnostics,
 ) {
 	if identity == nil {
 		return
 	}
 
-	idModel := buildIdentityModel(ctx, model)
+	idModel := buildIdentityModel(ctx, model, uid)
 	diagnostics.Append(identity.Set(ctx, idModel)...)
 }
 
@@ -256,7 +303,7 @@ func (r *manifestResource) IdentitySchema(
 	resp *resource.IdentitySchemaResponse,
 ) {
 	resp.IdentitySchema = identityschema.Schema{
-		Version: 1,
+		Version: 2,
 		Attributes: map[string]identityschema.Attribute{
 			"api_version": identityschema.StringAttribute{
 				RequiredForImport: true,
@@ -274,32 +321,45 @@ func (r *manifestResource) IdentitySchema(
 				OptionalForImport: true,
 				Description:       "Namespace of the Kubernetes resource. Empty for cluster-scoped resources.",
 			},
+			"uid": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description: "UID of the Kubernetes resource. Import fails if the object has " +
+					"another UID.",
+			},
 		},
 	}
 }
 
 // UpgradeIdentity returns identity upgraders for prior identity schema versions.
-// Version 0 represents the default state before identity was introduced.
+// Version 0 represents the default state before identity was introduced, and
+// version 1 the identity before uid was added.
 func (r *manifestResource) UpgradeIdentity(
 	ctx context.Context,
 ) map[int64]resource.IdentityUpgrader {
-	return map[int64]resource.IdentityUpgrader{
-		0: {
-			IdentityUpgrader: func(ctx context.Context, req resource.UpgradeIdentityRequest, resp *resource.UpgradeIdentityResponse) {
-				// Version 0 had no identity data. Set all attributes to null
-				// so the object itself is non-null (satisfying the framework's
-				// "Missing Upgraded Resource Identity" check) while being
-				// IsFullyNull (allowing Read to populate real values without
-				// triggering "unexpectedly returned a different identity").
-				resp.Diagnostics.Append(resp.Identity.Set(ctx, manifestIdentityModel{
-					APIVersion: types.StringNull(),
-					Kind:       types.StringNull(),
-					Name:       types.StringNull(),
-					Namespace:  types.StringNull(),
-				})...)
-			},
+	// Set all attributes to null so the object itself is non-null (satisfying
+	// the framework's "Missing Upgraded Resource Identity" check) while being
+	// IsFullyNull (allowing Read to populate real values, including the uid
+	// version 1 lacked, without triggering "unexpectedly returned a different
+	// identity").
+	nullIdentity := resource.IdentityUpgrader{
+		IdentityUpgrader: func(
+			ctx context.Context,
+			req resource.UpgradeIdentityRequest,
+			resp *resource.UpgradeIdentityResponse,
+		) {
+			resp.Diagnostics.Append(resp.Identity.Set(ctx, manifestIdentityModel{
+				APIVersion: types.StringNull(),
+				Kind:       types.StringNull(),
+				Name:       types.StringNull(),
+				Namespace:  types.StringNull(),
+				UID:        types.StringNull(),
+			})...)
 		},
 	}
+	return map[int64]resource.IdentityUpgrader{
+		0: nullIdentity,
+		1: nullIdentity,
+	}
 }
 
 // Schema defines the resource schema.
@@ -324,6 +384,8 @@ func (r *manifestResource) Schema(
 				Required: true,
 				MarkdownDescription: "An object representation of the Kubernetes resource manifest. " +
 					"Must contain `apiVersion`, `kind`, and `metadata` (with at least `name`). " +
+					"With `generateName` in place of `name`, the object is created under a name " +
+					"the API server generates, which is then found in `object`. " +
 					"Additional fields like `spec`, `data`, `stringData`, etc. depend on the resource kind.",
 			},
 			"status": schema.DynamicAttribute{
@@ -340,6 +402,22 @@ func (r *manifestResource) Schema(
 					dynamicplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"drift": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Fields of the manifest whose live value differed from the last applied value when the resource was last refreshed, similar to `kubectl diff`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Path of the field within the manifest, e.g. `spec.replicas`.",
+						},
+						"manager": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The field manager that owns the live value according to `metadata.managedFields`, or null if unknown.",
+						},
+					},
+				},
+			},
 			"fields": schema.SingleNestedAttribute{
 				Optional:            true,
 				MarkdownDescription: "Configure field tracking options.",
@@ -355,7 +433,8 @@ func (r *manifestResource) Schema(
 						Optional:    true,
 						MarkdownDescription: "List of manifest field paths that are immutable after creation. " +
 							"If any of these fields change, the resource will be replaced (destroyed and re-created). " +
-							"Uses dot-separated paths (e.g., `spec.selector`).",
+							"Uses dot-separated paths (e.g., `spec.selector`). Fields the schema " +
+							"declares immutable with the `self == oldSelf` rule need not be listed.",
 					},
 				},
 			},
@@ -389,19 +468,91 @@ func (r *manifestResource) Schema(
 					},
 				},
 			},
+			"plan_dry_run": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Send the manifest as a server-side dry-run apply during plan. Admission webhook " +
+					"rejections and quota errors are reported as plan errors, and `object` is planned as the " +
+					"result the API server would persist, including defaults and mutating webhook changes. " +
+					"Fields the apply may still set differently, such as allocated cluster IPs, " +
+					"fields owned by other field managers and, on create, fields the API server " +
+					"adds that the schema does not default, are planned as unknown. " +
+					"Defaults to the provider's `plan_dry_run`.",
+			},
+			"recreate_on_immutable_error": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Send changes to the manifest as a server-side dry-run apply " +
+					"during plan and replace the resource when the API server rejects them for " +
+					"changing an immutable field, such as a Job's `spec.template` or a Service's " +
+					"`clusterIP`, instead of failing the apply. Default: false",
+			},
+			"subresource": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Apply the manifest to this subresource of the object, " +
+					"`status` or `scale`, and read the object through it. The object must already " +
+					"exist and is left in place on destroy. For `scale`, the `spec.replicas` of " +
+					"the manifest is applied as an `autoscaling/v1` Scale.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(subresourceStatus, subresourceScale),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"create_policy": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "What to do when the object already exists on create: " +
+					"`adopt` takes it over, `fail_if_exists` fails, and `adopt_if_owned` takes it " +
+					"over only if its `" + ownerAnnotation + "` annotation names this resource's " +
+					"field manager, which requires `field_manager.name`. With `fail_if_exists` " +
+					"and `adopt_if_owned` the object is created with a POST, which fails if it " +
+					"exists, and the annotation is set on the objects this resource creates. " +
+					"Default: `adopt`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						createPolicyAdopt,
+						createPolicyFailIfExists,
+						createPolicyAdoptIfOwned,
+					),
+				},
+			},
+			"on_uid_change": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "What to do when refresh finds the object was deleted and " +
+					"recreated outside of Terraform, giving it another UID: `warn` reports it " +
+					"until an update of the resource takes over the new object, `replace` " +
+					"reports it and replaces the resource on the next apply, and `ignore` does " +
+					"nothing. Default: `warn`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						onUIDChangeWarn,
+						onUIDChangeReplace,
+						onUIDChangeIgnore,
+					),
+				},
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				Update: true,
 				Delete: true,
 			}),
 			"wait": schema.SingleNestedAttribute{
-				Optional:            true,
-				MarkdownDescription: "Configure waiter options. The apply will block until success conditions are met or the timeout is reached.",
+				Optional: true,
+				MarkdownDescription: "Configure waiter options. The apply will block until " +
+					"success conditions are met or the timeout is reached. Conditions and " +
+					"fields reported for a generation of the resource older than the one " +
+					"applied never match.",
 				Attributes: map[string]schema.Attribute{
 					"rollout": schema.BoolAttribute{
 						Optional:            true,
 						MarkdownDescription: "Wait for rollout to complete on resources that support `kubectl rollout status`.",
 					},
+					"ready": schema.BoolAttribute{
+						Optional: true,
+						MarkdownDescription: "Wait for the resource to become ready, following " +
+							"the kstatus rules: works for any kind, including custom resources " +
+							"that report `observedGeneration` and `Ready`, `Reconciling` or " +
+							"`Stalled` conditions.",
+					},
 					"fields": schema.ListNestedAttribute{
 						Optional: true,
 						MarkdownDescription: "Wait for a resource field to reach an expected value. " +
@@ -409,20 +560,48 @@ func (r *manifestResource) Schema(
 						NestedObject: schema.NestedAttributeObject{
 							Attributes: map[string]schema.Attribute{
 								"key": schema.StringAttribute{
-									Required:            true,
-									MarkdownDescription: "JSON path to the field to check (e.g., `status.phase`, `status.podIP`).",
+									Required: true,
+									MarkdownDescription: "JSON path to the field to check (e.g., " +
+										"`status.phase`, `status.podIP`). Use `[*]` to check the field " +
+										"in every element of a list (e.g., " +
+										"`status.loadBalancer.ingress[*].hostname`).",
 								},
 								"value": schema.StringAttribute{
-									Required:            true,
-									MarkdownDescription: "The expected value or regex pattern to match.",
+									Optional: true,
+									MarkdownDescription: "The expected value, regex pattern or number to " +
+										"compare with. Not used by `exists` and `not_exists`.",
 								},
 								"value_type": schema.StringAttribute{
-									Optional:            true,
-									Computed:            true,
-									Default:             stringdefault.StaticString("eq"),
-									MarkdownDescription: "Comparison type: `eq` for exact match (default) or `regex` for regular expression matching.",
+									Optional: true,
+									Computed: true,
+									Default:  stringdefault.StaticString(api.FieldEq),
+									MarkdownDescription: "Comparison type: `eq` for exact match (default), " +
+										"`ne` for anything else, `regex` for regular expression matching, " +
+										"`exists` or `not_exists` to check whether the field is set, or " +
+										"`gt`, `gte`, `lt` and `lte` to compare numerically.",
 									Validators: []validator.String{
-										stringvalidator.OneOf("eq", "regex"),
+										stringvalidator.OneOf(
+											api.FieldEq,
+											api.FieldNe,
+											api.FieldRegex,
+											api.FieldExists,
+											api.FieldNotExists,
+											api.FieldGt,
+											api.FieldGte,
+											api.FieldLt,
+											api.FieldLte,
+										),
+									},
+								},
+								"quantifier": schema.StringAttribute{
+									Optional: true,
+									Computed: true,
+									Default:  stringdefault.StaticString(quantifierAll),
+									MarkdownDescription: "For keys with `[*]`: `all` (default) to require a " +
+										"match in every list element, or `any` to require one. An empty " +
+										"list never matches.",
+									Validators: []validator.String{
+										stringvalidator.OneOf(quantifierAll, quantifierAny),
 									},
 								},
 							},
@@ -444,6 +623,12 @@ func (r *manifestResource) Schema(
 							},
 						},
 					},
+					"expression": schema.StringAttribute{
+						Optional: true,
+						MarkdownDescription: "Wait for a CEL expression to evaluate to true. " +
+							"The live object is bound to `self`, and the object as it was " +
+							"before the apply to `oldSelf`, which is null on create.",
+					},
 				},
 			},
 			"error": schema.SingleNestedAttribute{
@@ -494,11 +679,17 @@ func (r *manifestResource) Schema(
 							},
 						},
 					},
+					"expression": schema.StringAttribute{
+						Optional: true,
+						MarkdownDescription: "Fail if a CEL expression evaluates to true. " +
+							"The live object is bound to `self`, and the object as it was " +
+							"before the apply to `oldSelf`, which is null on create.",
+					},
 				},
 			},
 			"field_manager": schema.SingleNestedAttribute{
 				Optional:            true,
-				MarkdownDescription: "Configure field manager options for server-side apply.",
+				MarkdownDescription: "Configure field manager options for server-side apply. Drift is only reported for fields this field manager owns; fields taken over by another manager produce a warning instead.",
 				Attributes: map[string]schema.Attribute{
 					"name": schema.StringAttribute{
 						Optional:            true,
@@ -512,6 +703,14 @@ func (r *manifestResource) Schema(
 						Default:             booldefault.StaticBool(false),
 						MarkdownDescription: "Force changes against conflicts. Default: false",
 					},
+					"migrate_from": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						MarkdownDescription: "Field managers whose fields are handed over to this field manager " +
+							"before applying, such as `kubectl-client-side-apply` and `kubectl` for objects " +
+							"previously managed with client-side `kubectl apply`. Without this, fields removed " +
+							"from the manifest stay on the object while an old manager still owns them.",
+					},
 				},
 			},
 		},
@@ -557,6 +756,7 @@ func (r *manifestResource) ValidateConfig(
 	}
 
 	// Validate manifest has required fields: apiVersion, kind, metadata.name
+	// or metadata.generateName
 	if !config.Manifest.IsNull() && !config.Manifest.IsUnknown() {
 		manifestMap, d := dynamicToMap(ctx, config.Manifest)
 		resp.Diagnostics.Append(d...)
@@ -577,11 +777,19 @@ func (r *manifestResource) ValidateConfig(
 			}
 			if meta, ok := manifestMap["metadata"]; ok {
 				if metaMap, ok := meta.(map[string]any); ok {
-					if _, ok := metaMap["name"]; !ok {
+					_, hasName := metaMap["name"]
+					_, hasGenerateName := metaMap["generateName"]
+					if !hasName && !hasGenerateName {
 						resp.Diagnostics.AddAttributeError(
 							path.Root("manifest"),
 							"Missing required field",
-							"manifest.metadata must contain a 'name' field",
+							"manifest.metadata must contain a 'name' or 'generateName' field",
+						)
+					} else if !hasName && subresourceName(config.Subresource) != "" {
+						resp.Diagnostics.AddAttributeError(
+							path.Root("subresource"),
+							"Missing required field",
+							"subresource requires manifest.metadata to contain a 'name' field",
 						)
 					}
 				}
@@ -595,6 +803,26 @@ func (r *manifestResource) ValidateConfig(
 		}
 	}
 
+	// adopt_if_owned recognises objects by the field manager named in their
+	// owner annotation, which must not be the default shared by all resources.
+	if !config.CreatePolicy.IsUnknown() && createPolicy(&config) == createPolicyAdoptIfOwned &&
+		!config.FieldManager.IsUnknown() {
+		var fm fieldManagerModel
+		if !config.FieldManager.IsNull() {
+			resp.Diagnostics.Append(
+				config.FieldManager.As(ctx, &fm, basetypes.ObjectAsOptions{})...)
+		}
+		if config.FieldManager.IsNull() || fm.Name.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("create_policy"),
+				"Missing field manager name",
+				"create_policy \"adopt_if_owned\" requires field_manager.name to be set to a "+
+					"name unique to this resource, so that resources using the default field "+
+					"manager do not adopt each other's objects",
+			)
+		}
+	}
+
 	// Validate wait block — only one waiter type allowed
 	if !config.Wait.IsNull() && !config.Wait.IsUnknown() {
 		var w waitModel
@@ -605,12 +833,16 @@ func (r *manifestResource) ValidateConfig(
 			if !w.Rollout.IsNull() && w.Rollout.ValueBool() {
 				waiters++
 			}
+			if !w.Ready.IsNull() && w.Ready.ValueBool() {
+				waiters++
+			}
 			if !w.Fields.IsNull() && !w.Fields.IsUnknown() {
 				var fields []waitFieldModel
 				w.Fields.ElementsAs(ctx, &fields, false)
 				if len(fields) > 0 {
 					waiters++
 				}
+				resp.Diagnostics.Append(validateWaitFields(fields)...)
 			}
 			if !w.Conditions.IsNull() && !w.Conditions.IsUnknown() {
 				var conditions []waitConditionModel
@@ -619,15 +851,31 @@ func (r *manifestResource) ValidateConfig(
 					waiters++
 				}
 			}
+			if !w.Expression.IsNull() {
+				waiters++
+				resp.Diagnostics.Append(validateExpression(
+					path.Root("wait").AtName("expression"), w.Expression)...)
+			}
 			if waiters > 1 {
 				resp.Diagnostics.AddAttributeError(
 					path.Root("wait"),
 					"Invalid wait configuration",
-					"You may only set one of rollout, fields, or conditions in a wait block.",
+					"You may only set one of rollout, ready, fields, conditions, or expression "+
+						"in a wait block.",
 				)
 			}
 		}
 	}
+
+	if !config.Error.IsNull() && !config.Error.IsUnknown() {
+		var errOn errorModel
+		d := config.Error.As(ctx, &errOn, basetypes.ObjectAsOptions{})
+		resp.Diagnostics.Append(d...)
+		if !d.HasError() {
+			resp.Diagnostics.Append(validateExpression(
+				path.Root("error").AtName("expression"), errOn.Expression)...)
+		}
+	}
 }
 
 // Create creates a new Kubernetes resource.
@@ -701,25 +949,80 @@ func (r *manifestResource) Create(
 		backoffStrategy = backoff.WithMaxRetries(retryConfig, uint64(retryCount))
 	}
 
+	// An object planned from a dry run fixes the shape of the object read
+	// back after the apply.
+	plannedObject := plan.Object
+
+	// The object is checked against create_policy, and created first if the
+	// policy requires it, once, as an earlier attempt may have created it.
+	// Likewise an object named by metadata.generateName is created once, then
+	// applied under the name the API server assigned it.
+	userManifest := plan.Manifest
+	policyChecked := false
+	createdByPolicy := false
+	generatedName := ""
 	err := backoff.Retry(func() error {
-		err := r.applyManifest(createCtx, &plan, manifestWoMap)
+		if !policyChecked && !usesGenerateName(ctx, userManifest) {
+			created, err := r.createForPolicy(createCtx, &plan, manifestWoMap)
+			createdByPolicy = created
+			policyChecked = created || err == nil
+			var oee *ObjectExistsError
+			if errors.As(err, &oee) || isPermanentApplyError(err) {
+				return backoff.Permanent(err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if generatedName == "" && usesGenerateName(ctx, userManifest) {
+			name, err := r.createObject(createCtx, &plan, manifestWoMap)
+			if name != "" {
+				generatedName = name
+				resp.Diagnostics.Append(
+					resp.Private.SetKey(ctx, generatedNameKey, []byte(name))...)
+				var d diag.Diagnostics
+				plan.Manifest, d = setManifestName(ctx, userManifest, name)
+				resp.Diagnostics.Append(d...)
+			}
+			if isPermanentApplyError(err) {
+				return backoff.Permanent(err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		err := r.applyManifest(createCtx, &plan, manifestWoMap, createTimeout)
 		var ece *MatchingConditionError
-		if errors.As(err, &ece) {
+		if errors.As(err, &ece) || isPermanentApplyError(err) {
 			return backoff.Permanent(err)
 		}
 		return err
 	}, backoffStrategy)
+	plan.Manifest = userManifest
 	if err != nil {
 		// If the failure is an error condition match, save partial state so
 		// that the resource is tracked and ModifyPlan can schedule replacement
-		// on the next run.
+		// on the next run. Any other object created by now is saved as well.
 		var ece *MatchingConditionError
 		if errors.As(err, &ece) {
 			diags = resp.State.Set(ctx, plan)
 			resp.Diagnostics.Append(diags...)
 			resp.Diagnostics.Append(
 				resp.Private.SetKey(ctx, "error_condition_met", []byte("true"))...)
+		} else if generatedName != "" || createdByPolicy {
+			setPartialCreateState(ctx, resp, plan, generatedName, woKeys)
+		}
+		var oee *ObjectExistsError
+		if errors.As(err, &oee) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("create_policy"),
+				"Object Already Exists",
+				fmt.Sprintf("Could not create resource: %s.", err),
+			)
+			return
 		}
+		manifestMap, _ := dynamicToMap(ctx, plan.Manifest)
+		resp.Diagnostics.Append(applyErrorDiagnostics(err, manifestMap)...)
 		resp.Diagnostics.AddError(
 			"Failed to Create Resource",
 			fmt.Sprintf("Could not apply manifest: %s", err),
@@ -734,12 +1037,76 @@ func (r *manifestResource) Create(
 		}
 	}
 
+	// Record the UID of the created object, so that Read can tell when it is
+	// recreated outside of Terraform.
+	uid := objectUID(ctx, &plan)
+	if uid != "" {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, uidKey, []byte(uid))...)
+	}
+
+	plan.Object, diags = conformToPlannedObject(ctx, plannedObject, plan.Object)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 
 	// Set identity
-	setResponseIdentity(ctx, resp.Identity, &plan, &resp.Diagnostics)
+	setResponseIdentity(ctx, resp.Identity, &plan, uid, &resp.Diagnostics)
+}
+
+// setPartialCreateState saves plan as the state of an object Create created
+// but then failed to apply or wait for, so that Terraform taints the resource
+// and destroys the object rather than leave it behind and create another one
+// on the next apply. name is the name the API server generated, if any, and
+// woKeys the paths of the write-only fields to mask in object.
+func setPartialCreateState(
+	ctx context.Context,
+	resp *resource.CreateResponse,
+	plan manifestResourceModel,
+	name string,
+	woKeys []string,
+) {
+	named := plan
+	if name != "" {
+		var d diag.Diagnostics
+		named.Manifest, d = setManifestName(ctx, plan.Manifest, name)
+		resp.Diagnostics.Append(d...)
+	}
+	if plan.ID.IsNull() || plan.ID.IsUnknown() {
+		if uo, d := buildUnstructured(ctx, &named); !d.HasError() {
+			id := fmt.Sprintf("%s//%s//%s", uo.GetAPIVersion(), uo.GetKind(), uo.GetName())
+			if uo.GetNamespace() != "" {
+				id += "//" + uo.GetNamespace()
+			}
+			plan.ID = types.StringValue(id)
+		}
+	}
+	// Values the failed apply did not read back cannot be saved as unknown.
+	if v, err := plan.Object.ToTerraformValue(ctx); err != nil || !v.IsFullyKnown() {
+		plan.Object = types.DynamicNull()
+	}
+	if v, err := plan.Status.ToTerraformValue(ctx); err != nil || !v.IsFullyKnown() {
+		plan.Status = types.DynamicNull()
+	}
+	if plan.Drift.IsUnknown() {
+		plan.Drift = types.ListNull(types.ObjectType{AttrTypes: driftAttrTypes()})
+	}
+	if len(woKeys) > 0 {
+		if err := maskFieldsWoPaths(ctx, &plan, woKeys); err != nil {
+			log.Printf("[WARN] Failed to mask manifest_wo paths in object: %v", err)
+		}
+	}
+
+	uid := objectUID(ctx, &plan)
+	if uid != "" {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, uidKey, []byte(uid))...)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	setResponseIdentity(ctx, resp.Identity, &named, uid, &resp.Diagnostics)
 }
 
 // Read reads the current state of the resource.
@@ -774,6 +1141,10 @@ func (r *manifestResource) Read(
 	// Save prior manifest for reconciliation after read
 	priorManifest := state.Manifest
 
+	// Objects created from metadata.generateName are read by their assigned name.
+	state.Manifest, d = withGeneratedName(ctx, req.Private, state.Manifest)
+	resp.Diagnostics.Append(d...)
+
 	// Read from Kubernetes API
 	if err := r.readManifest(ctx, &state); err != nil {
 		// If resource not found, remove from state
@@ -796,9 +1167,39 @@ func (r *manifestResource) Read(
 		}
 	}
 
+	// Compare the live UID with the recorded one to detect objects recreated
+	// outside of Terraform. Resources created before UIDs were recorded
+	// adopt the live UID.
+	uid, d := recordedUID(ctx, req.Private)
+	resp.Diagnostics.Append(d...)
+	liveUID := objectUID(ctx, &state)
+	if uid == "" && liveUID != "" {
+		uid = liveUID
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, uidKey, []byte(uid))...)
+	}
+	kindAny, _ := extractManifestField(ctx, state.Manifest, "kind")
+	resp.Diagnostics.Append(uidChangeDiagnostics(fmt.Sprintf("%v", kindAny),
+		objectName(ctx, &state), uid, liveUID, onUIDChange(&state))...)
+
 	// Reconcile manifest: keep only attributes from prior state to avoid
 	// perpetual diffs from server-generated fields (uid, creationTimestamp, etc.)
-	state.Manifest = reconcileDynamicWithPrior(ctx, priorManifest, state.Manifest)
+	// Drift is limited to the fields our field manager still owns according
+	// to the live object's managedFields.
+	owners, err := fieldOwnershipFromObject(ctx, &state)
+	if err != nil {
+		log.Printf("[WARN] Failed to decode managedFields, comparing all fields: %v", err)
+		owners = nil
+	}
+	var drift []fieldDrift
+	state.Manifest, drift = reconcileDynamicWithPrior(ctx, priorManifest, state.Manifest, owners)
+	if msg := formatFieldTakeovers(drift); msg != "" {
+		resp.Diagnostics.AddWarning("Fields Managed by Another Field Manager", msg)
+	}
+	state.Drift, d = driftToList(ctx, drift)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// For immutable fields, restore the prior config value (from before Read)
 	// instead of keeping the API server's current value. This ensures that
@@ -816,7 +1217,7 @@ func (r *manifestResource) Read(
 	if r.providerData != nil && !state.Error.IsNull() && !state.Error.IsUnknown() {
 		var errOn errorModel
 		if d := state.Error.As(ctx, &errOn, basetypes.ObjectAsOptions{}); !d.HasError() {
-			var errorOnFields []waitFieldModel
+			var errorOnFields []errorFieldModel
 			var errorOnConditions []waitConditionModel
 			if !errOn.Fields.IsNull() && !errOn.Fields.IsUnknown() {
 				errOn.Fields.ElementsAs(ctx, &errorOnFields, false)
@@ -824,10 +1225,11 @@ func (r *manifestResource) Read(
 			if !errOn.Conditions.IsNull() && !errOn.Conditions.IsUnknown() {
 				errOn.Conditions.ElementsAs(ctx, &errorOnConditions, false)
 			}
-			if len(errorOnFields) > 0 || len(errorOnConditions) > 0 {
+			errorExpr, _ := compileExpression(errOn.Expression)
+			if len(errorOnFields) > 0 || len(errorOnConditions) > 0 || errorExpr != nil {
 				apiVersionAny, _ := extractManifestField(ctx, state.Manifest, "apiVersion")
 				kindAny, _ := extractManifestField(ctx, state.Manifest, "kind")
-				name, _ := extractManifestMetadataField(ctx, state.Manifest, "name")
+				name := objectName(ctx, &state)
 				namespace, _ := extractManifestMetadataField(ctx, state.Manifest, "namespace")
 
 				rs, err := r.getResourceInterface(
@@ -843,6 +1245,7 @@ func (r *manifestResource) Read(
 						name,
 						errorOnFields,
 						errorOnConditions,
+						errorExpr,
 					); err != nil {
 						errorConditionMet = true
 						resp.Diagnostics.AddWarning(
@@ -873,7 +1276,7 @@ func (r *manifestResource) Read(
 	}
 
 	// Set identity
-	setResponseIdentity(ctx, resp.Identity, &state, &resp.Diagnostics)
+	setResponseIdentity(ctx, resp.Identity, &state, uid, &resp.Diagnostics)
 }
 
 // Update updates an existing resource.
@@ -946,14 +1349,28 @@ func (r *manifestResource) Update(
 		backoffStrategy = backoff.WithMaxRetries(retryConfig, uint64(retryCount))
 	}
 
+	// An object planned from a dry run fixes the shape of the object read
+	// back after the apply.
+	plannedObject := plan.Object
+
+	// Objects created from metadata.generateName are applied under their
+	// assigned name.
+	userManifest := plan.Manifest
+	plan.Manifest, diags = withGeneratedName(ctx, req.Private, plan.Manifest)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := backoff.Retry(func() error {
-		err := r.applyManifest(updateCtx, &plan, manifestWoMap)
+		err := r.applyManifest(updateCtx, &plan, manifestWoMap, updateTimeout)
 		var ece *MatchingConditionError
-		if errors.As(err, &ece) {
+		if errors.As(err, &ece) || isPermanentApplyError(err) {
 			return backoff.Permanent(err)
 		}
 		return err
 	}, backoffStrategy)
+	plan.Manifest = userManifest
 	if err != nil {
 		// If the failure is an error condition match, save the current state so
 		// that the resource is tracked and ModifyPlan can schedule replacement
@@ -965,6 +1382,8 @@ func (r *manifestResource) Update(
 			resp.Diagnostics.Append(
 				resp.Private.SetKey(ctx, "error_condition_met", []byte("true"))...)
 		}
+		manifestMap, _ := dynamicToMap(ctx, plan.Manifest)
+		resp.Diagnostics.Append(applyErrorDiagnostics(err, manifestMap)...)
 		resp.Diagnostics.AddError(
 			"Failed to Update Resource",
 			fmt.Sprintf("Could not apply manifest: %s", err),
@@ -979,12 +1398,28 @@ func (r *manifestResource) Update(
 		}
 	}
 
+	// The apply took over the live object, so an object recreated outside of
+	// Terraform is managed from now on and Read stops warning about it.
+	uid := objectUID(ctx, &plan)
+	if uid != "" {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, uidKey, []byte(uid))...)
+	} else {
+		uid, diags = recordedUID(ctx, req.Private)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	plan.Object, diags = conformToPlannedObject(ctx, plannedObject, plan.Object)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set state
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 
 	// Set identity
-	setResponseIdentity(ctx, resp.Identity, &plan, &resp.Diagnostics)
+	setResponseIdentity(ctx, resp.Identity, &plan, uid, &resp.Diagnostics)
 }
 
 // Delete removes the resource.
@@ -1011,6 +1446,20 @@ func (r *manifestResource) Delete(
 		}
 	}
 
+	// Objects written through a subresource belong to someone else
+	if subresourceName(state.Subresource) != "" {
+		log.Printf("[INFO] subresource is set, leaving the object in place")
+		return
+	}
+
+	// Objects created from metadata.generateName are deleted by their
+	// assigned name.
+	state.Manifest, diags = withGeneratedName(ctx, req.Private, state.Manifest)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Delete the resource from Kubernetes
 	if err := r.deleteManifest(ctx, &state); err != nil {
 		// If not found, that's ok - already deleted
@@ -1030,7 +1479,7 @@ func (r *manifestResource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	var apiVersion, kind, name, namespace string
+	var apiVersion, kind, name, namespace, expectedUID string
 
 	// Support three import methods:
 	// 1. String ID with key=value pairs: apiVersion=<v>,kind=<k>,name=<n>[,namespace=<ns>]
@@ -1087,6 +1536,7 @@ func (r *manifestResource) ImportState(
 		if !identityModel.Namespace.IsNull() {
 			namespace = identityModel.Namespace.ValueString()
 		}
+		expectedUID = identityModel.UID.ValueString()
 	} else {
 		resp.Diagnostics.AddError(
 			"Invalid Import",
@@ -1111,17 +1561,24 @@ func (r *manifestResource) ImportState(
 		return
 	}
 
+	noDrift := types.ListValueMust(types.ObjectType{AttrTypes: driftAttrTypes()}, nil)
 	model := manifestResourceModel{
-		ID:           types.StringValue(req.ID),
-		Manifest:     manifestDynamic,
-		ManifestWo:   types.DynamicNull(),
-		Status:       types.DynamicNull(),
-		Object:       types.DynamicNull(),
-		Fields:       types.ObjectNull(fieldsAttrTypes()),
-		Delete:       types.ObjectNull(deleteAttrTypes()),
-		Wait:         types.ObjectNull(waitBlockAttrTypes()),
-		Error:        types.ObjectNull(errorAttrTypes()),
-		FieldManager: types.ObjectNull(fieldManagerBlockAttrTypes()),
+		ID:                       types.StringValue(req.ID),
+		Manifest:                 manifestDynamic,
+		ManifestWo:               types.DynamicNull(),
+		Status:                   types.DynamicNull(),
+		Object:                   types.DynamicNull(),
+		Drift:                    noDrift,
+		Fields:                   types.ObjectNull(fieldsAttrTypes()),
+		Delete:                   types.ObjectNull(deleteAttrTypes()),
+		Wait:                     types.ObjectNull(waitBlockAttrTypes()),
+		Error:                    types.ObjectNull(errorAttrTypes()),
+		FieldManager:             types.ObjectNull(fieldManagerBlockAttrTypes()),
+		PlanDryRun:               types.BoolNull(),
+		CreatePolicy:             types.StringNull(),
+		RecreateOnImmutableError: types.BoolNull(),
+		Subresource:              types.StringNull(),
+		OnUIDChange:              types.StringNull(),
 		Timeouts: timeouts.Value{
 			Object: types.ObjectNull(map[string]attr.Type{
 				"create": types.StringType,
@@ -1149,6 +1606,11 @@ func (r *manifestResource) ImportState(
 				return
 			}
 
+			uid := objectUID(ctx, &model)
+			if !recordImportedUID(ctx, resp, expectedUID, uid) {
+				return
+			}
+
 			resp.Diagnostics.AddWarning(
 				"Apply needed after 'import'",
 				"Please run apply after a successful import to realign the resource state to the configuration in Terraform.",
@@ -1158,7 +1620,7 @@ func (r *manifestResource) ImportState(
 			resp.Diagnostics.Append(diags...)
 
 			// Set identity
-			setResponseIdentity(ctx, resp.Identity, &model, &resp.Diagnostics)
+			setResponseIdentity(ctx, resp.Identity, &model, uid, &resp.Diagnostics)
 
 			// Mark as imported in private state for ModifyPlan
 			if resp.Private != nil {
@@ -1179,6 +1641,11 @@ func (r *manifestResource) ImportState(
 		return
 	}
 
+	uid := objectUID(ctx, &model)
+	if !recordImportedUID(ctx, resp, expectedUID, uid) {
+		return
+	}
+
 	resp.Diagnostics.AddWarning(
 		"Apply needed after 'import'",
 		"Please run apply after a successful import to realign the resource state to the configuration in Terraform.",
@@ -1189,7 +1656,7 @@ func (r *manifestResource) ImportState(
 	resp.Diagnostics.Append(diags...)
 
 	// Set identity
-	setResponseIdentity(ctx, resp.Identity, &model, &resp.Diagnostics)
+	setResponseIdentity(ctx, resp.Identity, &model, uid, &resp.Diagnostics)
 
 	// Mark as imported in private state for ModifyPlan
 	if resp.Private != nil {
@@ -1203,8 +1670,40 @@ func (r *manifestResource) ModifyPlan(
 	req resource.ModifyPlanRequest,
 	resp *resource.ModifyPlanResponse,
 ) {
-	// Only modify plan during updates (not create or destroy)
+	// Validate the manifest against its schema on create, and on update when
+	// it changed, so that a schema that got stricter since the last apply
+	// does not block plans of unchanged resources
+	if !req.Plan.Raw.IsNull() && r.providerData != nil {
+		var manifest, prior types.Dynamic
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("manifest"), &manifest)...)
+		if !req.State.Raw.IsNull() {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("manifest"), &prior)...)
+		}
+		if req.State.Raw.IsNull() || !manifest.Equal(prior) {
+			resp.Diagnostics.Append(
+				r.providerData.validateManifestAgainstSchema(ctx, manifest, prior)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Only modify plan during updates (not create or destroy), apart from
+	// planning the object from a server-side dry run on create
 	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		if !req.Plan.Raw.IsNull() && r.providerData != nil {
+			var plan manifestResourceModel
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			object, d := r.dryRunPlannedObject(ctx, req.Config, &plan)
+			resp.Diagnostics.Append(d...)
+			if !object.IsUnknown() {
+				resp.Diagnostics.Append(
+					resp.Plan.SetAttribute(ctx, path.Root("object"), object)...)
+			}
+		}
 		return
 	}
 
@@ -1274,6 +1773,16 @@ func (r *manifestResource) ModifyPlan(
 		}
 	}
 
+	// If Read found the object was recreated outside of Terraform, replace it
+	// when on_uid_change asks for that.
+	if onUIDChange(&plan) == onUIDChangeReplace {
+		uid, d := recordedUID(ctx, req.Private)
+		resp.Diagnostics.Append(d...)
+		if uidChanged(uid, objectUID(ctx, &state)) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("manifest"))
+		}
+	}
+
 	// Check fields.immutable — if any listed field changed, require replacement
 	if !plan.Fields.IsNull() && !plan.Fields.IsUnknown() {
 		var fm fieldsModel
@@ -1372,12 +1881,44 @@ func (r *manifestResource) ModifyPlan(
 		}
 	}
 
+	// Objects created from metadata.generateName are dry-run under their
+	// assigned name.
+	dryRunPlan := plan
+	dryRunPlan.Manifest, diags = withGeneratedName(ctx, req.Private, plan.Manifest)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if hasChange && r.providerData != nil && len(resp.RequiresReplace) == 0 {
+		replace, d := r.immutableFieldChanged(ctx, req.Config, &dryRunPlan)
+		resp.Diagnostics.Append(d...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if replace {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("manifest"))
+		}
+	}
+
 	if hasChange {
 		plan.Status = types.DynamicUnknown()
 		plan.Object = types.DynamicUnknown()
+		if r.providerData != nil && len(resp.RequiresReplace) == 0 {
+			object, d := r.dryRunPlannedObject(ctx, req.Config, &dryRunPlan)
+			resp.Diagnostics.Append(d...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			plan.Object = object
+		}
+		// Applying reconciles the live object with the manifest.
+		plan.Drift, diags = driftToList(ctx, nil)
+		resp.Diagnostics.Append(diags...)
 	} else {
 		plan.Status = state.Status
 		plan.Object = state.Object
+		plan.Drift = state.Drift
 	}
 
 	diags = resp.Plan.Set(ctx, plan)
@@ -1732,6 +2273,7 @@ func (r *manifestResource) modifyPlanWithOpenAPI(
 
 	if hasPrior {
 		// Update plan: compare prior manifest with proposed manifest and use prior object values
+		immutableChanged := false
 		completePlan, err = tftypes.Transform(
 			completePlan,
 			func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
@@ -1765,6 +2307,11 @@ func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
 							!wasCfg.(tftypes.Value).Equal(nowCfg.(tftypes.Value))
 						if hasChanged {
 							h, ok := hints[morph.ValueToTypePath(ap).String()]
+							// Transition rules only apply to fields set before and after.
+							if ok && h == api.ImmutableHint && !wasCfg.(tftypes.Value).IsNull() &&
+								!nowCfg.(tftypes.Value).IsNull() {
+								immutableChanged = true
+							}
 							if ok && h == api.PreserveUnknownFieldsLabel {
 								resp.Diagnostics.AddWarning(
 									fmt.Sprintf(
@@ -1812,6 +2359,9 @@ func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
 			log.Printf("[DEBUG] Could not apply Update tree traversal: %v", err)
 			return
 		}
+		if immutableChanged {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("manifest"))
+		}
 	} else {
 		// Create plan (no prior state): just mark computed_fields as unknown
 		completePlan, err = tftypes.Transform(
@@ -1922,13 +2472,49 @@ func (r *manifestResource) getResourceInterface(
 	return rcl, nil
 }
 
+// crdGroupKind identifies CustomResourceDefinition objects.
+var crdGroupKind = k8sschema.GroupKind{
+	Group: "apiextensions.k8s.io",
+	Kind:  "CustomResourceDefinition",
+}
+
+// waitForCRDEstablished blocks until the named CRD reports the Established
+// condition, bounded by timeout.
+func (r *manifestResource) waitForCRDEstablished(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	name string,
+	timeout time.Duration,
+) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	log.Printf("[INFO] Waiting for CustomResourceDefinition %s to be established", name)
+	waiter := &api.ConditionsWaiterV2{
+		Resource:     rs,
+		ResourceName: name,
+		Conditions:   []api.ConditionMatcher{{Type: "Established", Status: "True"}},
+		Logger:       r.providerData.logger,
+	}
+	if err := waiter.Wait(timeoutCtx); err != nil {
+		return fmt.Errorf(
+			"failed waiting for CustomResourceDefinition %s to be established: %w",
+			name,
+			err,
+		)
+	}
+	return nil
+}
+
 // applyManifest applies the manifest to Kubernetes using server-side apply,
 // then handles wait conditions. error_on conditions are checked continuously
-// while waiting for success conditions to be met.
+// while waiting for success conditions to be met. Waits are bounded by
+// timeout, the timeout of the current operation.
 func (r *manifestResource) applyManifest(
 	ctx context.Context,
 	model *manifestResourceModel,
 	manifestWoMap map[string]any,
+	timeout time.Duration,
 ) error {
 	// Save user-provided manifest before readManifest overwrites it.
 	// The API response includes server-generated fields (uid, creationTimestamp, etc.)
@@ -1962,47 +2548,47 @@ func (r *manifestResource) applyManifest(
 	log.Printf("[DEBUG] Applying Kubernetes resource: %s/%s", uo.GetKind(), uo.GetName())
 
 	// Get field manager configuration
-	fieldManagerName := "Terraform"
-	forceConflicts := false
-
-	if !model.FieldManager.IsNull() {
-		var fm fieldManagerModel
-		diags := model.FieldManager.As(ctx, &fm, basetypes.ObjectAsOptions{})
-		if diags.HasError() {
-			return fmt.Errorf("failed to parse field_manager: %v", diags)
-		}
-		if !fm.Name.IsNull() {
-			fieldManagerName = fm.Name.ValueString()
-		}
-		if !fm.ForceConflicts.IsNull() {
-			forceConflicts = fm.ForceConflicts.ValueBool()
-		}
+	fieldManagerName, forceConflicts, err := fieldManagerSettings(ctx, model)
+	if err != nil {
+		return err
 	}
-
-	// Create REST client for this resource type
-	manifest := yaml.NewFromUnstructured(uo)
-	mainClientset, err := r.providerData.getMainClientset()
+	migrateFrom, err := fieldManagerMigrateFrom(ctx, model)
 	if err != nil {
-		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
+		return err
 	}
-	restCfg, err := r.providerData.getRestConfig()
+	setOwnerAnnotation(uo, createPolicy(model), fieldManagerName)
+	waitExpr, errorExpr, err := modelExpressions(ctx, model)
 	if err != nil {
-		return fmt.Errorf("failed to get kubernetes REST config: %w", err)
+		return err
 	}
-	restClient := api.GetRestClientFromUnstructured(
-		ctx,
-		manifest,
-		mainClientset,
-		restCfg,
-	)
+
+	// Create REST client for this resource type
+	manifest := yaml.NewFromUnstructured(uo)
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, manifest)
 	if restClient.Error != nil {
 		return fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
 	}
 
+	if len(migrateFrom) > 0 {
+		err := migrateFieldManagers(ctx, restClient.ResourceInterface, uo.GetName(),
+			migrateFrom, fieldManagerName)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Expressions referring to oldSelf see the object as it was before the apply.
+	err = setExpressionsOldSelf(ctx, restClient.ResourceInterface, uo.GetName(),
+		waitExpr, errorExpr)
+	if err != nil {
+		return err
+	}
+
 	// Remove nulls from the object before applying
+	subresource := subresourceName(model.Subresource)
 	content := uo.UnstructuredContent()
 	cleanedContent := api.MapRemoveNulls(content)
-	uo.SetUnstructuredContent(cleanedContent)
+	uo.SetUnstructuredContent(subresourceBody(cleanedContent, subresource))
 
 	// Marshal to JSON for server-side apply
 	jsonData, err := uo.MarshalJSON()
@@ -2020,6 +2606,7 @@ func (r *manifestResource) applyManifest(
 			FieldManager: fieldManagerName,
 			Force:        &forceConflicts,
 		},
+		subresourceArgs(subresource)...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to apply manifest: %w", err)
@@ -2028,6 +2615,17 @@ func (r *manifestResource) applyManifest(
 	log.Printf("[DEBUG] Successfully applied resource: %s/%s (UID: %s)",
 		result.GetKind(), result.GetName(), result.GetUID())
 
+	// A CRD is only usable once it is established. Wait for that before
+	// returning, so that custom resources applied later in the same run can be
+	// mapped and typed, then drop the schema caches that predate it.
+	if result.GroupVersionKind().GroupKind() == crdGroupKind {
+		err := r.waitForCRDEstablished(ctx, restClient.ResourceInterface, result.GetName(), timeout)
+		if err != nil {
+			return err
+		}
+		r.providerData.invalidateSchemaCaches(result)
+	}
+
 	// Read back to populate computed fields (ID, status, object) from server response
 	if err := r.readManifest(ctx, model); err != nil {
 		return fmt.Errorf("failed to read manifest after apply: %w", err)
@@ -2037,7 +2635,7 @@ func (r *manifestResource) applyManifest(
 	model.Manifest = plannedManifest
 
 	// Parse error conditions from top-level attribute
-	var errorOnFields []waitFieldModel
+	var errorOnFields []errorFieldModel
 	var errorOnConditions []waitConditionModel
 	hasErrorOn := false
 	if !model.Error.IsNull() && !model.Error.IsUnknown() {
@@ -2050,7 +2648,7 @@ func (r *manifestResource) applyManifest(
 			if !errOn.Conditions.IsNull() && !errOn.Conditions.IsUnknown() {
 				errOn.Conditions.ElementsAs(ctx, &errorOnConditions, false)
 			}
-			hasErrorOn = len(errorOnFields) > 0 || len(errorOnConditions) > 0
+			hasErrorOn = len(errorOnFields) > 0 || len(errorOnConditions) > 0 || errorExpr != nil
 		}
 	}
 
@@ -2069,12 +2667,7 @@ func (r *manifestResource) applyManifest(
 		return nil
 	}
 
-	createTimeout, d := model.Timeouts.Create(ctx, 10*time.Minute)
-	if d.HasError() {
-		return fmt.Errorf("failed to get create timeout: %v", d)
-	}
-
-	timeoutCtx, cancel := context.WithTimeout(ctx, createTimeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Get name/namespace for log messages
@@ -2085,45 +2678,18 @@ func (r *manifestResource) applyManifest(
 	kind := fmt.Sprintf("%v", kindAny)
 	apiVersion := fmt.Sprintf("%v", apiVersionAny)
 
-	// Handle rollout wait using RolloutWaiter (polymorphichelpers)
-	if hasWait && !wait.Rollout.IsNull() && wait.Rollout.ValueBool() {
-		log.Printf("[INFO] Waiting for rollout of %s/%s", kind, name)
-
-		rs, err := r.getResourceInterface(ctx, apiVersion, kind, namespace)
-		if err != nil {
-			return fmt.Errorf("failed to set up rollout wait: %w", err)
-		}
-
-		waiter := &api.RolloutWaiter{
-			Resource:     rs,
-			ResourceName: name,
-			Logger:       r.providerData.logger,
-		}
-
-		if hasErrorOn {
-			if err := r.waitWithErrorCheck(
-				timeoutCtx,
-				rs,
-				waiter,
-				name,
-				errorOnFields,
-				errorOnConditions,
-			); err != nil {
-				return fmt.Errorf("failed to wait for rollout: %w", err)
-			}
-		} else {
-			if err := waiter.Wait(timeoutCtx); err != nil {
-				return fmt.Errorf("failed to wait for rollout: %w", err)
-			}
-		}
-
-		log.Printf("[INFO] Rollout complete for %s/%s", kind, name)
+	rs, err := r.getResourceInterface(ctx, apiVersion, kind, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to set up wait: %w", err)
 	}
 
-	// Handle condition and field waits
+	// Build the waiter for the wait block, at most one kind of wait is set
 	var conditions []waitConditionModel
 	var waitFields []waitFieldModel
+	var rollout, ready bool
 	if hasWait {
+		rollout = wait.Rollout.ValueBool()
+		ready = wait.Ready.ValueBool()
 		if !wait.Conditions.IsNull() {
 			wait.Conditions.ElementsAs(ctx, &conditions, false)
 		}
@@ -2132,114 +2698,88 @@ func (r *manifestResource) applyManifest(
 		}
 	}
 
-	if len(conditions) > 0 || len(waitFields) > 0 {
-		log.Printf("[INFO] Waiting for conditions/fields on %s/%s", kind, name)
-
-		rs, err := r.getResourceInterface(ctx, apiVersion, kind, namespace)
+	// Get OpenAPI type for field wait
+	var objectType tftypes.Type
+	var typeHints map[string]string
+	if len(waitFields) > 0 {
+		gvk := k8sschema.FromAPIVersionAndKind(apiVersion, kind)
+		objectType, typeHints, err = r.providerData.TFTypeFromOpenAPI(ctx, gvk, true)
 		if err != nil {
-			return fmt.Errorf("failed to set up condition/field wait: %w", err)
+			log.Printf("[WARN] Could not resolve OpenAPI type for field wait: %v", err)
+			objectType = tftypes.DynamicPseudoType
 		}
+	}
 
-		// Get OpenAPI type for field wait
-		var objectType tftypes.Type
-		var typeHints map[string]string
-		if len(waitFields) > 0 {
-			gvk := k8sschema.FromAPIVersionAndKind(apiVersion, kind)
-			objectType, typeHints, err = r.providerData.TFTypeFromOpenAPI(ctx, gvk, true)
-			if err != nil {
-				log.Printf("[WARN] Could not resolve OpenAPI type for field wait: %v", err)
-				objectType = tftypes.DynamicPseudoType
-			}
+	// Build field matchers
+	var fieldMatchers []api.FieldMatcher
+	for _, f := range waitFields {
+		m, err := fieldMatcherFromModel(f)
+		if err != nil {
+			return err
 		}
+		fieldMatchers = append(fieldMatchers, m)
+	}
 
-		// Build field matchers
-		var fieldMatchers []api.FieldMatcher
-		for _, f := range waitFields {
-			valueType := "eq"
-			if !f.ValueType.IsNull() && f.ValueType.ValueString() != "" {
-				valueType = f.ValueType.ValueString()
-			}
-
-			p, pErr := api.FieldPathToTftypesPath(f.Key.ValueString())
-			if pErr != nil {
-				return fmt.Errorf("invalid field path %q: %w", f.Key.ValueString(), pErr)
-			}
-
-			var re *regexp.Regexp
-			if valueType == "regex" {
-				re, err = regexp.Compile(f.Value.ValueString())
-				if err != nil {
-					return fmt.Errorf("invalid regex %q: %w", f.Value.ValueString(), err)
-				}
-			} else {
-				// For eq, create a regex that matches exactly
-				re = regexp.MustCompile("^" + regexp.QuoteMeta(f.Value.ValueString()) + "$")
-			}
+	// Build condition matchers
+	var conditionMatchers []api.ConditionMatcher
+	for _, c := range conditions {
+		conditionMatchers = append(conditionMatchers, api.ConditionMatcher{
+			Type:   c.Type.ValueString(),
+			Status: c.Status.ValueString(),
+		})
+	}
 
-			fieldMatchers = append(fieldMatchers, api.FieldMatcher{
-				Path:         p,
-				ValueMatcher: re,
-			})
-		}
+	waiter := api.NewResourceWaiterFromConfig(
+		rs,
+		name,
+		result.GetGeneration(),
+		objectType,
+		typeHints,
+		rollout,
+		ready,
+		fieldMatchers,
+		conditionMatchers,
+		waitExpr,
+		r.providerData.logger,
+	)
 
-		// Build condition matchers
-		var conditionMatchers []api.ConditionMatcher
-		for _, c := range conditions {
-			conditionMatchers = append(conditionMatchers, api.ConditionMatcher{
-				Type:   c.Type.ValueString(),
-				Status: c.Status.ValueString(),
-			})
+	if _, ok := waiter.(*api.NoopWaiter); ok {
+		if !hasErrorOn {
+			return nil
 		}
-
-		// Create waiter
-		waiter := api.NewResourceWaiterFromConfig(
+		// error_on conditions without any wait block — check once
+		return checkErrorOnConditions(
+			ctx,
 			rs,
 			name,
-			objectType,
-			typeHints,
-			false, // rollout already handled above
-			fieldMatchers,
-			conditionMatchers,
-			r.providerData.logger,
+			errorOnFields,
+			errorOnConditions,
+			errorExpr,
 		)
+	}
 
-		// Run waiter with error_on checking if configured
-		if hasErrorOn {
-			err = r.waitWithErrorCheck(
-				timeoutCtx,
-				rs,
-				waiter,
-				name,
-				errorOnFields,
-				errorOnConditions,
-			)
-		} else {
-			err = waiter.Wait(timeoutCtx)
-		}
-
-		if err != nil {
-			return fmt.Errorf("failed to wait for conditions: %w", err)
-		}
-
-		log.Printf("[INFO] Conditions/fields met for %s/%s", kind, name)
-	} else if hasErrorOn {
-		// error_on conditions without any wait block — check once
-		rs, err := r.getResourceInterface(ctx, apiVersion, kind, namespace)
-		if err != nil {
-			return fmt.Errorf("failed to set up error_on check: %w", err)
-		}
+	log.Printf("[INFO] Waiting for %s/%s", kind, name)
 
-		if err := checkErrorOnConditions(
-			ctx,
+	// Run waiter with error_on checking if configured
+	if hasErrorOn {
+		err = r.waitWithErrorCheck(
+			timeoutCtx,
 			rs,
+			waiter,
 			name,
 			errorOnFields,
 			errorOnConditions,
-		); err != nil {
-			return err
-		}
+			errorExpr,
+		)
+	} else {
+		err = waiter.Wait(timeoutCtx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s/%s: %w", kind, name, err)
 	}
 
+	log.Printf("[INFO] Done waiting for %s/%s", kind, name)
+
 	return nil
 }
 
@@ -2249,15 +2789,28 @@ func checkErrorOnConditions(
 	ctx context.Context,
 	rs dynamic.ResourceInterface,
 	name string,
-	errorFields []waitFieldModel,
+	errorFields []errorFieldModel,
 	errorConditions []waitConditionModel,
+	errorExpr *api.Expression,
 ) error {
 	res, err := rs.Get(ctx, name, meta_v1.GetOptions{})
 	if err != nil {
 		// Can't check, don't fail
 		return nil //nolint:nilerr
 	}
+	return matchErrorConditions(res, name, errorFields, errorConditions, errorExpr)
+}
 
+// matchErrorConditions checks error_on field and condition matchers and the
+// error expression against res, the named resource. Returns an error if any
+// error condition is matched.
+func matchErrorConditions(
+	res *meta_v1_unstruct.Unstructured,
+	name string,
+	errorFields []errorFieldModel,
+	errorConditions []waitConditionModel,
+	errorExpr *api.Expression,
+) error {
 	yamlJSON, err := res.MarshalJSON()
 	if err != nil {
 		// Can't check, don't fail
@@ -2340,47 +2893,33 @@ func checkErrorOnConditions(
 		}
 	}
 
-	return nil
+	return matchErrorExpression(res, name, errorExpr)
 }
 
-// waitWithErrorCheck runs the waiter while also checking for error_on conditions.
+// waitWithErrorCheck runs the waiter while also checking for error_on
+// conditions. Both are evaluated on every change of the resource seen by a
+// single watch, with error conditions taking precedence.
 func (r *manifestResource) waitWithErrorCheck(
 	ctx context.Context,
 	rs dynamic.ResourceInterface,
-	waiter api.Waiter,
+	waiter api.ObjectWaiter,
 	name string,
-	errorFields []waitFieldModel,
+	errorFields []errorFieldModel,
 	errorConditions []waitConditionModel,
+	errorExpr *api.Expression,
 ) error {
-	errCh := make(chan error, 1)
-
-	// Run the waiter in a goroutine
-	go func() {
-		errCh <- waiter.Wait(ctx)
-	}()
-
-	// Check error_on conditions periodically while waiter runs
-	ticker := time.NewTicker(api.WaiterSleepTime)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case err := <-errCh:
-			return err
-		case <-ticker.C:
-			if err := checkErrorOnConditions(
-				ctx,
-				rs,
-				name,
-				errorFields,
-				errorConditions,
-			); err != nil {
-				return err
+	err := api.WaitForObject(ctx, rs, name, waiter.Reason(),
+		func(res *meta_v1_unstruct.Unstructured) (bool, error) {
+			err := matchErrorConditions(res, name, errorFields, errorConditions, errorExpr)
+			if err != nil {
+				return false, err
 			}
-		case <-ctx.Done():
-			return fmt.Errorf("%s timed out waiting for resource", name)
-		}
+			return waiter.Done(res)
+		})
+	if te, ok := waiter.(api.TimeoutExplainer); ok {
+		return te.TimeoutError(err)
 	}
+	return err
 }
 
 // readManifestV2 reads a Kubernetes resource and populates the state using Dynamic attributes.
@@ -2416,30 +2955,14 @@ func (r *manifestResource) readManifestV2(
 	}
 
 	manifest := yaml.NewFromUnstructured(tempUo)
-	mainClientset, err := r.providerData.getMainClientset()
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
-	}
-	restCfg, err := r.providerData.getRestConfig()
-	if err != nil {
-		return fmt.Errorf("failed to get kubernetes REST config: %w", err)
-	}
-	restClient := api.GetRestClientFromUnstructured(
-		ctx,
-		manifest,
-		mainClientset,
-		restCfg,
-	)
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, manifest)
 	if restClient.Error != nil {
 		return fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
 	}
 
 	// Get the resource from Kubernetes
-	result, err := restClient.ResourceInterface.Get(
-		ctx,
-		name,
-		meta_v1.GetOptions{},
-	)
+	result, err := getObject(ctx, restClient.ResourceInterface,
+		k8sschema.FromAPIVersionAndKind(apiVersion, kind), name, subresourceName(model.Subresource))
 	if err != nil {
 		return err
 	}
@@ -2541,13 +3064,11 @@ func (r *manifestResource) readManifestWithOpenAPI(
 	}
 
 	// Get resource from API
-	var result *meta_v1_unstruct.Unstructured
-	rcl := client.Resource(gvr)
+	var rs dynamic.ResourceInterface = client.Resource(gvr)
 	if ns && namespace != "" {
-		result, err = rcl.Namespace(namespace).Get(ctx, name, meta_v1.GetOptions{})
-	} else {
-		result, err = rcl.Get(ctx, name, meta_v1.GetOptions{})
+		rs = client.Resource(gvr).Namespace(namespace)
 	}
+	result, err := getObject(ctx, rs, gvk, name, subresourceName(model.Subresource))
 	if err != nil {
 		return err
 	}
@@ -2651,6 +3172,13 @@ func (r *manifestResource) setStateFromOpenAPIResult(
 		model.Object = objectDynamic
 	}
 
+	// Drift against the prior manifest is computed by Read.
+	drift, d := driftToList(ctx, nil)
+	diags.Append(d...)
+	if !diags.HasError() {
+		model.Drift = drift
+	}
+
 	if diags.HasError() {
 		return fmt.Errorf("failed to set state: %v", diags)
 	}
@@ -2690,20 +3218,7 @@ func (r *manifestResource) deleteManifest(
 	}
 
 	manifest := yaml.NewFromUnstructured(uo)
-	mainClientset, err := r.providerData.getMainClientset()
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
-	}
-	restCfg, err := r.providerData.getRestConfig()
-	if err != nil {
-		return fmt.Errorf("failed to get kubernetes REST config: %w", err)
-	}
-	restClient := api.GetRestClientFromUnstructured(
-		ctx,
-		manifest,
-		mainClientset,
-		restCfg,
-	)
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, manifest)
 	if restClient.Error != nil {
 		return fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
 	}
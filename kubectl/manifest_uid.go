@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// Values of on_uid_change, which decides what Read does when the object was
+// deleted and recreated under the same name outside of Terraform.
+const (
+	onUIDChangeWarn    = "warn"
+	onUIDChangeReplace = "replace"
+	onUIDChangeIgnore  = "ignore"
+)
+
+// uidKey is the private state key holding the UID of the object the
+// resource created or imported.
+const uidKey = "uid"
+
+// onUIDChange returns the on_uid_change of model, warn when unset.
+func onUIDChange(model *manifestResourceModel) string {
+	if model.OnUIDChange.IsNull() || model.OnUIDChange.IsUnknown() {
+		return onUIDChangeWarn
+	}
+	return model.OnUIDChange.ValueString()
+}
+
+// objectUID returns the UID of the live object read into model, "" when it
+// has not been read.
+func objectUID(ctx context.Context, model *manifestResourceModel) string {
+	if model.Object.IsNull() || model.Object.IsUnknown() {
+		return ""
+	}
+	uid, _ := extractManifestMetadataField(ctx, model.Object, "uid")
+	return uid
+}
+
+// recordedUID returns the UID stored in private, "" for resources created
+// before UIDs were recorded.
+func recordedUID(ctx context.Context, private privateStateGetter) (string, diag.Diagnostics) {
+	uid, diags := private.GetKey(ctx, uidKey)
+	return string(uid), diags
+}
+
+// uidChanged reports whether live, the UID of the live object, differs from
+// recorded, the UID of the object the resource manages.
+func uidChanged(recorded, live string) bool {
+	return recorded != "" && live != "" && recorded != live
+}
+
+// uidChangeDiagnostics returns the warning Read reports under policy when the
+// object was recreated outside of Terraform, changing its UID from recorded
+// to live.
+func uidChangeDiagnostics(kind, name, recorded, live, policy string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if policy == onUIDChangeIgnore || !uidChanged(recorded, live) {
+		return diags
+	}
+	detail := fmt.Sprintf("%s %q has UID %s, but Terraform manages UID %s. It was deleted and "+
+		"recreated outside of Terraform, which breaks owner references and bindings to the "+
+		"old object.", kind, name, live, recorded)
+	if policy == onUIDChangeReplace {
+		detail += " Resource will be replaced on next apply."
+	} else {
+		detail += " Replace the resource, or set on_uid_change to replace, to manage the " +
+			"new object."
+	}
+	diags.AddAttributeWarning(path.Root("on_uid_change"), "Object Recreated Outside Terraform",
+		detail)
+	return diags
+}
+
+// recordImportedUID records uid, the UID of an imported object, in private
+// state. It returns false, with an error, when the import identity expected
+// another UID.
+func recordImportedUID(
+	ctx context.Context,
+	resp *resource.ImportStateResponse,
+	expected, uid string,
+) bool {
+	if uidChanged(expected, uid) {
+		resp.Diagnostics.AddError(
+			"Object UID Mismatch",
+			fmt.Sprintf("The object to import has UID %s, but the identity expects UID %s.",
+				uid, expected),
+		)
+		return false
+	}
+	if uid != "" && resp.Private != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, uidKey, []byte(uid))...)
+	}
+	return true
+}
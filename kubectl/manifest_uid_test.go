@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUIDChangeDiagnostics(t *testing.T) {
+	samples := map[string]struct {
+		recorded string
+		live     string
+		policy   string
+		expected string
+	}{
+		"unchanged": {
+			recorded: "a1",
+			live:     "a1",
+			policy:   onUIDChangeWarn,
+		},
+		"not recorded": {
+			live:   "b2",
+			policy: onUIDChangeWarn,
+		},
+		"warn": {
+			recorded: "a1",
+			live:     "b2",
+			policy:   onUIDChangeWarn,
+			expected: "set on_uid_change to replace",
+		},
+		"replace": {
+			recorded: "a1",
+			live:     "b2",
+			policy:   onUIDChangeReplace,
+			expected: "will be replaced on next apply",
+		},
+		"ignore": {
+			recorded: "a1",
+			live:     "b2",
+			policy:   onUIDChangeIgnore,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			diags := uidChangeDiagnostics("Job", "migrate", s.recorded, s.live, s.policy)
+			if s.expected == "" {
+				if len(diags) != 0 {
+					t.Fatalf("expected no diagnostics, got %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags.HasError() {
+				t.Fatalf("expected a single warning, got %v", diags)
+			}
+			if detail := diags[0].Detail(); !strings.Contains(detail, s.expected) ||
+				!strings.Contains(detail, "has UID b2") {
+				t.Fatalf("unexpected warning %q", detail)
+			}
+		})
+	}
+}
+
+func TestObjectUID(t *testing.T) {
+	ctx := context.Background()
+	object, diags := mapToDynamic(ctx, map[string]any{
+		"metadata": map[string]any{"name": "migrate", "uid": "a1"},
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if uid := objectUID(ctx, &manifestResourceModel{Object: object}); uid != "a1" {
+		t.Fatalf("expected UID a1, got %q", uid)
+	}
+	if uid := objectUID(ctx, &manifestResourceModel{Object: types.DynamicUnknown()}); uid != "" {
+		t.Fatalf("expected no UID for an unknown object, got %q", uid)
+	}
+}
+
+func TestRecordImportedUID(t *testing.T) {
+	ctx := context.Background()
+	resp := &resource.ImportStateResponse{}
+	if !recordImportedUID(ctx, resp, "", "a1") || resp.Diagnostics.HasError() {
+		t.Fatalf("expected import without a UID to succeed, got %v", resp.Diagnostics)
+	}
+	if !recordImportedUID(ctx, resp, "a1", "a1") || resp.Diagnostics.HasError() {
+		t.Fatalf("expected import of the same UID to succeed, got %v", resp.Diagnostics)
+	}
+	if recordImportedUID(ctx, resp, "a1", "b2") || !resp.Diagnostics.HasError() {
+		t.Fatal("expected import of another UID to fail")
+	}
+}
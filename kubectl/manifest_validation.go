@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// validateManifestAgainstSchema checks the planned manifest against the
+// OpenAPI or CRD schema of its kind, so that typos and invalid values are
+// reported at plan time instead of failing the apply. Validation is skipped
+// when the schema cannot be resolved, e.g. for a CRD created in the same apply.
+//
+// The x-kubernetes-validations CEL rules of the schema are evaluated as well,
+// with transition rules comparing against prior, the manifest in state.
+func (p *kubectlProviderData) validateManifestAgainstSchema(
+	ctx context.Context,
+	manifest types.Dynamic,
+	prior types.Dynamic,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	manifestMap, d := dynamicToMap(ctx, manifest)
+	diags.Append(d...)
+	if diags.HasError() || manifestMap == nil {
+		return diags
+	}
+
+	apiVersion, _ := manifestMap["apiVersion"].(string)
+	kind, _ := manifestMap["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		return diags
+	}
+	gvk := k8sschema.FromAPIVersionAndKind(apiVersion, kind)
+
+	sch, defs, err := p.SchemaFromOpenAPI(ctx, gvk)
+	if err != nil {
+		log.Printf("[DEBUG] Could not resolve OpenAPI schema for %s, skipping validation: %v",
+			gvk.String(), err)
+		return diags
+	}
+
+	errs := api.ValidateAgainstSchema(manifestMap, sch, defs)
+
+	// transition rules only apply when the object is updated in place
+	priorMap, d := dynamicToMap(ctx, prior)
+	diags.Append(d...)
+	if priorMap != nil && (priorMap["apiVersion"] != apiVersion || priorMap["kind"] != kind) {
+		priorMap = nil
+	}
+	errs = append(errs, api.ValidateCELRules(manifestMap, priorMap, sch, defs)...)
+
+	for _, e := range errs {
+		if e.Warning {
+			diags.AddAttributeWarning(manifestAttributePath(e.Path), e.Summary, e.Detail)
+			continue
+		}
+		diags.AddAttributeError(manifestAttributePath(e.Path), e.Summary, e.Detail)
+	}
+	return diags
+}
+
+// manifestAttributePath converts a path within a manifest into the
+// framework path of the corresponding element of the manifest attribute.
+func manifestAttributePath(ap *tftypes.AttributePath) path.Path {
+	p := path.Root("manifest")
+	for _, s := range ap.Steps() {
+		switch st := s.(type) {
+		case tftypes.AttributeName:
+			p = p.AtName(string(st))
+		case tftypes.ElementKeyString:
+			p = p.AtMapKey(string(st))
+		case tftypes.ElementKeyInt:
+			p = p.AtListIndex(int(st))
+		}
+	}
+	return p
+}
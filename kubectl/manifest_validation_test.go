@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestManifestAttributePath(t *testing.T) {
+	ap := tftypes.NewAttributePath().
+		WithAttributeName("spec").
+		WithAttributeName("containers").
+		WithElementKeyInt(0).
+		WithAttributeName("image")
+	expected := path.Root("manifest").
+		AtName("spec").
+		AtName("containers").
+		AtListIndex(0).
+		AtName("image")
+	if got := manifestAttributePath(ap); !got.Equal(expected) {
+		t.Fatalf("expected %s, got %s", expected, got)
+	}
+}
+
+// validationTestProvider returns provider data that resolves the Widget
+// kind from a local CRD and cannot reach a cluster.
+func validationTestProvider(t *testing.T) *kubectlProviderData {
+	t.Helper()
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "Widget"},
+			"versions": []any{map[string]any{
+				"name": "v1",
+				"schema": map[string]any{"openAPIV3Schema": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"spec": map[string]any{
+							"type":     "object",
+							"required": []any{"size"},
+							"properties": map[string]any{
+								"replicas": map[string]any{"type": "integer"},
+								"size": map[string]any{
+									"type": "string",
+									"enum": []any{"small", "large"},
+								},
+								"tags": map[string]any{
+									"type":  "array",
+									"items": map[string]any{"type": "string"},
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}}
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	_, _ = p.localSchemas.Get(func() (*localSchemas, error) {
+		return &localSchemas{crds: []*unstructured.Unstructured{crd}}, nil
+	})
+	return p
+}
+
+func TestValidateManifestAgainstSchema(t *testing.T) {
+	ctx := context.Background()
+	p := validationTestProvider(t)
+	spec := path.Root("manifest").AtName("spec")
+
+	samples := map[string]struct {
+		kind     string
+		spec     map[string]any
+		expected map[string]path.Path
+	}{
+		"valid": {
+			spec: map[string]any{"size": "small", "replicas": float64(2), "tags": []any{"a"}},
+		},
+		"wrong type": {
+			spec:     map[string]any{"size": "small", "replicas": "two"},
+			expected: map[string]path.Path{"Invalid field type": spec.AtName("replicas")},
+		},
+		"enum": {
+			spec:     map[string]any{"size": "medium"},
+			expected: map[string]path.Path{"Value not allowed": spec.AtName("size")},
+		},
+		"required": {
+			spec:     map[string]any{"replicas": float64(1)},
+			expected: map[string]path.Path{"Missing required field": spec},
+		},
+		"unknown field": {
+			spec:     map[string]any{"size": "small", "replica": float64(1)},
+			expected: map[string]path.Path{"Unknown field": spec.AtName("replica")},
+		},
+		"list item": {
+			spec: map[string]any{"size": "small", "tags": []any{"a", true}},
+			expected: map[string]path.Path{
+				"Invalid field type": spec.AtName("tags").AtListIndex(1),
+			},
+		},
+		"unresolvable schema is skipped": {
+			kind: "Gadget",
+			spec: map[string]any{"anything": true},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			kind := s.kind
+			if kind == "" {
+				kind = "Widget"
+			}
+			manifest, d := mapToDynamic(ctx, map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       kind,
+				"metadata":   map[string]any{"name": "w"},
+				"spec":       s.spec,
+			})
+			if d.HasError() {
+				t.Fatalf("failed to build manifest: %v", d)
+			}
+
+			diags := p.validateManifestAgainstSchema(ctx, manifest, types.DynamicNull())
+			if len(diags) != len(s.expected) {
+				t.Fatalf("expected %d diagnostics, got %v", len(s.expected), diags)
+			}
+			for _, dg := range diags {
+				want, ok := s.expected[dg.Summary()]
+				if !ok {
+					t.Fatalf("unexpected diagnostic %v", dg)
+				}
+				withPath, ok := dg.(interface{ Path() path.Path })
+				if !ok || !withPath.Path().Equal(want) {
+					t.Fatalf("expected %q on %s, got %v", dg.Summary(), want, dg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Values of wait.fields[].quantifier, which decides whether a field behind a
+// splat has to match in all of the list elements or in any of them.
+const (
+	quantifierAll = "all"
+	quantifierAny = "any"
+)
+
+// fieldMatcherFromModel builds the matcher of a wait.fields entry.
+func fieldMatcherFromModel(f waitFieldModel) (api.FieldMatcher, error) {
+	key := f.Key.ValueString()
+	value := f.Value.ValueString()
+	operator := api.FieldEq
+	if !f.ValueType.IsNull() && f.ValueType.ValueString() != "" {
+		operator = f.ValueType.ValueString()
+	}
+
+	paths, err := api.FieldPathToTftypesPaths(key)
+	if err != nil {
+		return api.FieldMatcher{}, fmt.Errorf("invalid field path %q: %w", key, err)
+	}
+	m := api.FieldMatcher{
+		Path:     paths[0],
+		Splats:   paths[1:],
+		Operator: operator,
+		Any:      f.Quantifier.ValueString() == quantifierAny,
+	}
+
+	switch operator {
+	case api.FieldExists, api.FieldNotExists:
+	case api.FieldGt, api.FieldGte, api.FieldLt, api.FieldLte:
+		n, ok := new(big.Float).SetString(value)
+		if !ok {
+			return api.FieldMatcher{}, fmt.Errorf("invalid number %q for %s", value, operator)
+		}
+		m.Number = n
+	case api.FieldRegex:
+		m.ValueMatcher, err = regexp.Compile(value)
+		if err != nil {
+			return api.FieldMatcher{}, fmt.Errorf("invalid regex %q: %w", value, err)
+		}
+	default:
+		// For eq and ne, create a regex that matches exactly
+		m.ValueMatcher = regexp.MustCompile("^" + regexp.QuoteMeta(value) + "$")
+	}
+	return m, nil
+}
+
+// validateWaitFields checks that each of fields, the entries of wait.fields,
+// has a value its comparison can use.
+func validateWaitFields(fields []waitFieldModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, f := range fields {
+		if f.Key.IsUnknown() || f.Value.IsUnknown() || f.ValueType.IsUnknown() {
+			continue
+		}
+		p := path.Root("wait").AtName("fields").AtListIndex(i)
+		operator := f.ValueType.ValueString()
+		if operator == "" {
+			operator = api.FieldEq
+		}
+		if f.Value.IsNull() && operator != api.FieldExists && operator != api.FieldNotExists {
+			diags.AddAttributeError(p.AtName("value"), "Missing wait field value",
+				fmt.Sprintf("A value is required to compare %s with %s.", f.Key.ValueString(),
+					operator))
+			continue
+		}
+		if _, err := fieldMatcherFromModel(f); err != nil {
+			diags.AddAttributeError(p, "Invalid wait field", err.Error())
+		}
+	}
+	return diags
+}
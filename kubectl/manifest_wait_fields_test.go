@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func waitField(key, valueType string, value types.String) waitFieldModel {
+	return waitFieldModel{
+		Key:        types.StringValue(key),
+		Value:      value,
+		ValueType:  types.StringValue(valueType),
+		Quantifier: types.StringValue(quantifierAny),
+	}
+}
+
+func TestFieldMatcherFromModel(t *testing.T) {
+	m, err := fieldMatcherFromModel(
+		waitField("status.loadBalancer.ingress[*].hostname", "exists", types.StringNull()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Splats) != 1 || !m.Any {
+		t.Fatalf("expected a matcher over any list element, got %+v", m)
+	}
+
+	m, err = fieldMatcherFromModel(waitField("status.readyReplicas", "gte", types.StringValue("3")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := m.Number.Int64(); n != 3 {
+		t.Fatalf("expected the number 3, got %v", m.Number)
+	}
+
+	_, err = fieldMatcherFromModel(waitField("status.readyReplicas", "gt", types.StringValue("x")))
+	if err == nil {
+		t.Fatal("expected an error for a non-numeric value")
+	}
+}
+
+func TestValidateWaitFields(t *testing.T) {
+	samples := map[string]struct {
+		field waitFieldModel
+		valid bool
+	}{
+		"exists without value": {
+			field: waitField("status.podIP", "exists", types.StringNull()),
+			valid: true,
+		},
+		"eq without value": {
+			field: waitField("status.phase", "", types.StringNull()),
+		},
+		"gte with number": {
+			field: waitField("status.readyReplicas", "gte", types.StringValue("3")),
+			valid: true,
+		},
+		"lt with text": {
+			field: waitField("status.readyReplicas", "lt", types.StringValue("three")),
+		},
+		"invalid regex": {
+			field: waitField("status.phase", "regex", types.StringValue("(")),
+		},
+		"unknown value": {
+			field: waitField("status.phase", "eq", types.StringUnknown()),
+			valid: true,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			diags := validateWaitFields([]waitFieldModel{s.field})
+			if diags.HasError() == s.valid {
+				t.Fatalf("expected valid to be %t, got %v", s.valid, diags)
+			}
+		})
+	}
+}
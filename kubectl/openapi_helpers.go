@@ -5,11 +5,15 @@
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -22,55 +26,18 @@ func (p *kubectlProviderData) TFTypeFromOpenAPI(
 	gvk schema.GroupVersionKind,
 	status bool,
 ) (tftypes.Type, map[string]string, error) {
-	var tsch tftypes.Type
-	var hints map[string]string
-
-	oapi, err := p.getOAPIv2Foundry()
+	f, err := p.foundryForGVK(ctx, gvk)
 	if err != nil {
-		return nil, hints, fmt.Errorf("cannot get OpenAPI foundry: %s", err)
+		return nil, nil, err
 	}
-	// check if GVK is from a CRD
-	crdSchema, err := p.lookUpGVKinCRDs(ctx, gvk)
+	tsch, hints, err := f.GetTypeByGVK(gvk)
 	if err != nil {
 		return nil, hints, fmt.Errorf(
-			"failed to look up GVK [%s] among available CRDs: %s",
+			"cannot get resource type from OpenAPI (%s): %s",
 			gvk.String(),
 			err,
 		)
 	}
-	if crdSchema != nil {
-		crdMap, ok := crdSchema.(map[string]any)
-		if !ok {
-			return nil, hints, fmt.Errorf("CRD schema is not a map[string]any: %T", crdSchema)
-		}
-		js, err := json.Marshal(api.SchemaToSpec("", crdMap))
-		if err != nil {
-			return nil, hints, fmt.Errorf("CRD schema fails to marshal into JSON: %s", err)
-		}
-		oapiv3, err := api.NewFoundryFromSpecV3(js)
-		if err != nil {
-			return nil, hints, err
-		}
-		tsch, hints, err = oapiv3.GetTypeByGVK(gvk)
-		if err != nil {
-			return nil, hints, fmt.Errorf(
-				"failed to generate tftypes for GVK [%s] from CRD schema: %s",
-				gvk.String(),
-				err,
-			)
-		}
-	}
-	if tsch == nil {
-		// Not a CRD type - look GVK up in cluster OpenAPI spec
-		tsch, hints, err = oapi.GetTypeByGVK(gvk)
-		if err != nil {
-			return nil, hints, fmt.Errorf(
-				"cannot get resource type from OpenAPI (%s): %s",
-				gvk.String(),
-				err,
-			)
-		}
-	}
 	// remove "status" attribute from resource type when not requested
 	if tsch.Is(tftypes.Object{}) && !status {
 		ot, ok := tsch.(tftypes.Object)
@@ -92,6 +59,101 @@ func (p *kubectlProviderData) TFTypeFromOpenAPI(
 	return tsch, hints, nil
 }
 
+// SchemaFromOpenAPI returns the OpenAPI schema of the resource designated by
+// gvk, resolved from the same sources as TFTypeFromOpenAPI.
+func (p *kubectlProviderData) SchemaFromOpenAPI(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+) (*openapi3.Schema, map[string]*openapi3.SchemaRef, error) {
+	f, err := p.foundryForGVK(ctx, gvk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.GetSchemaByGVK(gvk)
+}
+
+// foundryForGVK returns the foundry describing gvk. Local schema_sources take
+// precedence over the cluster; CRD schemas take precedence over the OpenAPI v3
+// document of the group version, which in turn takes precedence over the
+// cluster's OpenAPI v2 spec.
+func (p *kubectlProviderData) foundryForGVK(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+) (api.Foundry, error) {
+	local, err := p.getLocalSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load schema_sources: %s", err)
+	}
+
+	// check if GVK is from a CRD
+	crdSchema := local.crdSchema(gvk)
+	if crdSchema == nil {
+		if f := local.foundryByGVK(gvk); f != nil {
+			return f, nil
+		}
+		crdSchema, err = p.lookUpGVKinCRDs(ctx, gvk)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to look up GVK [%s] among available CRDs: %s",
+				gvk.String(),
+				err,
+			)
+		}
+	}
+	if crdSchema != nil {
+		crdMap, ok := crdSchema.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("CRD schema is not a map[string]any: %T", crdSchema)
+		}
+		js, err := json.Marshal(api.SchemaToSpec("", crdMap))
+		if err != nil {
+			return nil, fmt.Errorf("CRD schema fails to marshal into JSON: %s", err)
+		}
+		return p.crdFoundry(gvk, js)
+	}
+
+	// Not a CRD type - look GVK up in the OpenAPI v3 document for its group version
+	oapiv3, err := p.getOAPIv3Foundry(gvk.GroupVersion())
+	if err != nil {
+		p.logger.Debug("cannot get OpenAPI v3 foundry, falling back to OpenAPI v2",
+			"gvk", gvk.String(), "error", err)
+	}
+	if oapiv3 != nil {
+		if _, _, err := oapiv3.GetSchemaByGVK(gvk); err == nil {
+			return oapiv3, nil
+		}
+		p.logger.Debug("falling back to OpenAPI v2", "gvk", gvk.String())
+	}
+
+	// No OpenAPI v3 type available - look GVK up in cluster OpenAPI v2 spec
+	oapi, err := p.getOAPIv2Foundry()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get OpenAPI foundry: %s", err)
+	}
+	return oapi, nil
+}
+
+// crdFoundryKey identifies the foundry built from a CRD schema.
+type crdFoundryKey struct {
+	gvk schema.GroupVersionKind
+	sum [sha256.Size]byte
+}
+
+// crdFoundry returns the foundry for spec, the OpenAPI v3 document built from
+// the CRD schema of gvk. Foundries are kept per schema so that their type
+// cache is shared by all lookups, which matters for large CRDs.
+func (p *kubectlProviderData) crdFoundry(
+	gvk schema.GroupVersionKind,
+	spec []byte,
+) (api.Foundry, error) {
+	key := crdFoundryKey{gvk: gvk, sum: sha256.Sum256(spec)}
+	c, _ := p.crdFoundries.LoadOrStore(key, &cache[api.Foundry]{})
+	fc := c.(*cache[api.Foundry]) //nolint:forcetypeassert
+	return fc.Get(func() (api.Foundry, error) {
+		return api.NewFoundryFromSpecV3(spec)
+	})
+}
+
 // RemoveServerSideFields removes certain fields which get added to the
 // resource after creation which would cause a perpetual diff.
 func RemoveServerSideFields(in map[string]any) map[string]any {
@@ -108,8 +170,9 @@ func RemoveServerSideFields(in map[string]any) map[string]any {
 			delete(meta, "generation")
 			delete(meta, "selfLink")
 
-			// TODO: we should be filtering API responses based on the contents of 'managedFields'
-			// and only retain the attributes for which the manager is Terraform
+			// managedFields are not part of the manifest. Read consults them
+			// on the object attribute to limit drift to the fields owned by
+			// our field manager (see reconcileDynamicWithPrior).
 			delete(meta, "managedFields")
 		}
 	}
@@ -117,94 +180,159 @@ func RemoveServerSideFields(in map[string]any) map[string]any {
 	return in
 }
 
+// lookUpGVKinCRDs returns the OpenAPI v3 schema of gvk when it is served by a
+// CRD, or nil for built-in types and non-structural CRDs. Only the CRD backing
+// the kind is fetched, by resolving its plural through the RESTMapper, and the
+// result is cached per GVK.
 func (p *kubectlProviderData) lookUpGVKinCRDs(
 	ctx context.Context,
 	gvk schema.GroupVersionKind,
 ) (any, error) {
-	// check CRD versions
-	crds, err := p.fetchCRDs(ctx)
+	c, _ := p.crdSchemas.LoadOrStore(gvk, &cache[any]{})
+	sc := c.(*cache[any]) //nolint:forcetypeassert
+	crdSchema, err := sc.Get(func() (any, error) {
+		crd, err := p.fetchCRD(ctx, gvk.GroupKind())
+		if err != nil || crd == nil {
+			return nil, err
+		}
+		return crdVersionSchema(crd, gvk.Version), nil
+	})
 	if err != nil {
-		return nil, err
+		sc.Reset()
 	}
+	return crdSchema, err
+}
 
-	for _, r := range crds {
-		spec, ok := r.Object["spec"].(map[string]any)
-		if !ok || spec == nil {
-			continue
-		}
-		grp, _ := spec["group"].(string)
-		if grp != gvk.Group {
-			continue
-		}
-		names, ok := spec["names"].(map[string]any)
-		if !ok || names == nil {
-			continue
+// crdVersionSchema extracts the openAPIV3Schema of the given version from a CRD.
+func crdVersionSchema(crd *unstructured.Unstructured, version string) any {
+	spec, ok := crd.Object["spec"].(map[string]any)
+	if !ok || spec == nil {
+		return nil
+	}
+	ver := spec["versions"]
+	if ver == nil {
+		ver = spec["version"]
+		if ver == nil {
+			return nil
 		}
-		kind, _ := names["kind"].(string)
-		if kind != gvk.Kind {
+	}
+	verList, ok := ver.([]any)
+	if !ok {
+		return nil
+	}
+	for _, rv := range verList {
+		if rv == nil {
 			continue
 		}
-		ver := spec["versions"]
-		if ver == nil {
-			ver = spec["version"]
-			if ver == nil {
-				continue
-			}
-		}
-		verList, ok := ver.([]any)
+		v, ok := rv.(map[string]any)
 		if !ok {
 			continue
 		}
-		for _, rv := range verList {
-			if rv == nil {
-				continue
-			}
-			v, ok := rv.(map[string]any)
+		if v["name"] == version {
+			s, ok := v["schema"].(map[string]any)
 			if !ok {
-				continue
-			}
-			if v["name"] == gvk.Version {
-				s, ok := v["schema"].(map[string]any)
-				if !ok {
-					return nil, nil // non-structural CRD
-				}
-				return s["openAPIV3Schema"], nil
+				return nil // non-structural CRD
 			}
+			return s["openAPIV3Schema"]
 		}
 	}
-	return nil, nil
+	return nil
 }
 
-func (p *kubectlProviderData) fetchCRDs(ctx context.Context) ([]unstructured.Unstructured, error) {
-	return p.crds.Get(func() ([]unstructured.Unstructured, error) {
-		c, err := p.getDynamicClient()
-		if err != nil {
-			return nil, err
-		}
-		m, err := p.getRestMapper()
-		if err != nil {
-			return nil, err
-		}
+// crdCacheKeyPrefix is the on-disk cache directory holding CRDs by name.
+const crdCacheKeyPrefix = "crds/"
 
-		crd := schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
-		crms, err := m.RESTMappings(crd)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"could not extract resource version mappings for apiextensions.k8s.io.CustomResourceDefinition: %s",
-				err,
-			)
+// builtinGroups are the API groups served by the Kubernetes API server
+// itself. They are never served by a CRD, so looking one up is a wasted
+// request. Groups are matched exactly: CRDs may use other *.k8s.io groups
+// once approved, e.g. gateway.networking.k8s.io.
+var builtinGroups = map[string]bool{
+	"":                             true,
+	"admissionregistration.k8s.io": true,
+	"apiextensions.k8s.io":         true,
+	"apiregistration.k8s.io":       true,
+	"apps":                         true,
+	"authentication.k8s.io":        true,
+	"authorization.k8s.io":         true,
+	"autoscaling":                  true,
+	"batch":                        true,
+	"certificates.k8s.io":          true,
+	"coordination.k8s.io":          true,
+	"discovery.k8s.io":             true,
+	"events.k8s.io":                true,
+	"extensions":                   true,
+	"flowcontrol.apiserver.k8s.io": true,
+	"internal.apiserver.k8s.io":    true,
+	"metrics.k8s.io":               true,
+	"networking.k8s.io":            true,
+	"node.k8s.io":                  true,
+	"policy":                       true,
+	"rbac.authorization.k8s.io":    true,
+	"resource.k8s.io":              true,
+	"scheduling.k8s.io":            true,
+	"storage.k8s.io":               true,
+	"storagemigration.k8s.io":      true,
+}
+
+// fetchCRD returns the CRD that defines gk, named <plural>.<group>, or nil
+// when gk is not served by a CRD.
+func (p *kubectlProviderData) fetchCRD(
+	ctx context.Context,
+	gk schema.GroupKind,
+) (*unstructured.Unstructured, error) {
+	if builtinGroups[gk.Group] {
+		return nil, nil
+	}
+
+	m, err := p.getRestMapper()
+	if err != nil {
+		return nil, err
+	}
+	rm, err := m.RESTMapping(gk)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve resource name for %s: %s", gk, err)
+	}
+	name := rm.Resource.Resource + "." + gk.Group
+
+	dc := p.getDiskCache()
+	if body, _, fresh, ok := dc.read(crdCacheKeyPrefix + name); ok && fresh {
+		crd := &unstructured.Unstructured{}
+		if err := crd.UnmarshalJSON(body); err == nil {
+			return crd, nil
 		}
+		dc.invalidate(crdCacheKeyPrefix + name)
+	}
 
-		var crds []unstructured.Unstructured
-		for _, crm := range crms {
-			crdRes, err := c.Resource(crm.Resource).List(ctx, v1.ListOptions{})
-			if err != nil {
-				return nil, err
-			}
+	c, err := p.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	crm, err := m.RESTMapping(crdGroupKind)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not extract resource version mappings for apiextensions.k8s.io.CustomResourceDefinition: %s",
+			err,
+		)
+	}
 
-			crds = append(crds, crdRes.Items...)
+	crd, err := c.Resource(crm.Resource).Get(ctx, name, v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if dc != nil {
+		if body, err := crd.MarshalJSON(); err == nil {
+			if err := dc.write(crdCacheKeyPrefix+name, body, ""); err != nil {
+				p.logger.Warn("failed to cache CRD on disk", "name", name, "error", err)
+			}
 		}
+	}
 
-		return crds, nil
-	})
+	return crd, nil
 }
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCRDVersionSchema(t *testing.T) {
+	v1Schema := map[string]any{"type": "object"}
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"versions": []any{
+				map[string]any{
+					"name":   "v1",
+					"schema": map[string]any{"openAPIV3Schema": v1Schema},
+				},
+				map[string]any{"name": "v1alpha1"},
+			},
+		},
+	}}
+
+	if got := crdVersionSchema(crd, "v1"); !reflect.DeepEqual(got, v1Schema) {
+		t.Fatalf("unexpected schema for v1: %v", got)
+	}
+	if got := crdVersionSchema(crd, "v1alpha1"); got != nil {
+		t.Fatalf("expected nil schema for non-structural version, got %v", got)
+	}
+	if got := crdVersionSchema(crd, "v2"); got != nil {
+		t.Fatalf("expected nil schema for unknown version, got %v", got)
+	}
+}
+
+func TestCRDFoundryCache(t *testing.T) {
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+	f1, err := p.crdFoundry(gvk, []byte(widgetSpecV3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f2, _ := p.crdFoundry(gvk, []byte(widgetSpecV3)); f2 != f1 {
+		t.Fatal("expected the foundry of an unchanged schema to be reused")
+	}
+
+	changed := strings.Replace(widgetSpecV3, `"type": "object"}`, `"type": "string"}`, 1)
+	if f3, _ := p.crdFoundry(gvk, []byte(changed)); f3 == f1 {
+		t.Fatal("expected a new foundry for a changed schema")
+	}
+
+	p.invalidateSchemaCaches(&unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"group": "example.com",
+			"names": map[string]any{"kind": "Widget"},
+		},
+	}})
+	if f4, _ := p.crdFoundry(gvk, []byte(widgetSpecV3)); f4 == f1 {
+		t.Fatal("expected the foundry to be rebuilt after the CRD changed")
+	}
+}
+
+func TestFetchCRDSkipsBuiltinGroups(t *testing.T) {
+	// No cluster is configured, so any request would fail.
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	for _, gk := range []schema.GroupKind{
+		{Kind: "ConfigMap"},
+		{Group: "apps", Kind: "Deployment"},
+		{Group: "networking.k8s.io", Kind: "Ingress"},
+		{Group: "rbac.authorization.k8s.io", Kind: "Role"},
+	} {
+		crd, err := p.fetchCRD(t.Context(), gk)
+		if crd != nil || err != nil {
+			t.Errorf("%s: expected no CRD lookup, got %v, %v", gk, crd, err)
+		}
+	}
+
+	gk := schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "Gateway"}
+	if _, err := p.fetchCRD(t.Context(), gk); err == nil {
+		t.Errorf("%s: expected a CRD lookup", gk)
+	}
+}
+
+func TestLookUpGVKinCRDsRetriesErrors(t *testing.T) {
+	// No cluster is configured, so the CRD lookup fails.
+	p := &kubectlProviderData{logger: hclog.NewNullLogger()}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	if _, err := p.lookUpGVKinCRDs(t.Context(), gvk); err == nil {
+		t.Fatal("expected the CRD lookup to fail")
+	}
+	c, ok := p.crdSchemas.Load(gvk)
+	if !ok {
+		t.Fatal("expected a cache entry for the GVK")
+	}
+	if c.(*cache[any]).done {
+		t.Fatal("expected the failed lookup not to be cached")
+	}
+}
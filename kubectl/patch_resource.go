@@ -16,6 +16,7 @@
 	yamlpkg "github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -28,6 +29,7 @@
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -50,6 +52,7 @@ type patchResourceModel struct {
 	Kind         types.String   `tfsdk:"kind"`
 	Name         types.String   `tfsdk:"name"`
 	Namespace    types.String   `tfsdk:"namespace"`
+	Subresource  types.String   `tfsdk:"subresource"`
 	Patch        types.Dynamic  `tfsdk:"patch"`
 	Object       types.Dynamic  `tfsdk:"object"`
 	FieldManager types.List     `tfsdk:"field_manager"`
@@ -123,6 +126,18 @@ func (r *patchResource) Schema(
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"subresource": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Patch and read this subresource of the target resource, " +
+					"`status` or `scale`, instead of the resource itself. For `scale`, the " +
+					"`spec.replicas` of the patch is applied to the `autoscaling/v1` Scale.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(subresourceStatus, subresourceScale),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"patch": schema.DynamicAttribute{
 				Required: true,
 				MarkdownDescription: "An object containing the fields to patch on the target resource. " +
@@ -469,6 +484,11 @@ func (r *patchResource) buildPatchUnstructured(
 	return uo, diags
 }
 
+// patchTargetGVK returns the group, version and kind of the target resource.
+func patchTargetGVK(model *patchResourceModel) k8sschema.GroupVersionKind {
+	return k8sschema.FromAPIVersionAndKind(model.APIVersion.ValueString(), model.Kind.ValueString())
+}
+
 // getRestClient creates a REST client for the target resource.
 func (r *patchResource) getRestClient(
 	ctx context.Context,
@@ -484,20 +504,7 @@ func (r *patchResource) getRestClient(
 	}
 
 	manifest := yamlpkg.NewFromUnstructured(tempUo)
-	mainClientset, err := r.providerData.getMainClientset()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
-	}
-	restCfg, err := r.providerData.getRestConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get kubernetes REST config: %w", err)
-	}
-	restClient := api.GetRestClientFromUnstructured(
-		ctx,
-		manifest,
-		mainClientset,
-		restCfg,
-	)
+	restClient := r.providerData.getRestClientFromUnstructured(ctx, manifest)
 	if restClient.Error != nil {
 		return nil, fmt.Errorf("failed to create kubernetes rest client: %w", restClient.Error)
 	}
@@ -526,11 +533,9 @@ func (r *patchResource) applyPatch(
 	}
 
 	// Fetch the current resource so we can merge array fields rather than replace them.
-	current, err := restClient.ResourceInterface.Get(
-		ctx,
-		model.Name.ValueString(),
-		meta_v1.GetOptions{},
-	)
+	subresource := subresourceName(model.Subresource)
+	current, err := getObject(ctx, restClient.ResourceInterface, patchTargetGVK(model),
+		model.Name.ValueString(), subresource)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current resource for array merge: %w", err)
 	}
@@ -548,7 +553,7 @@ func (r *patchResource) applyPatch(
 	// Merge array fields in the patch with their live counterparts so we append
 	// rather than overwrite. Scalar and map fields pass through unchanged.
 	mergedContent := mergeArraysWithCurrent(current.Object, uo.Object)
-	uo.SetUnstructuredContent(mergedContent)
+	uo.SetUnstructuredContent(subresourceBody(mergedContent, subresource))
 
 	log.Printf("[DEBUG] Applying patch to %s/%s/%s",
 		model.APIVersion.ValueString(), model.Kind.ValueString(), model.Name.ValueString())
@@ -571,10 +576,12 @@ func (r *patchResource) applyPatch(
 		meta_v1.PatchOptions{
 			FieldManager: fieldManagerName,
 		},
+		subresourceArgs(subresource)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to apply patch: %w", err)
 	}
+	result = subresourceObject(result, patchTargetGVK(model), subresource)
 
 	log.Printf("[DEBUG] Successfully applied patch to: %s/%s (UID: %s)",
 		result.GetKind(), result.GetName(), result.GetUID())
@@ -596,11 +603,8 @@ func (r *patchResource) readPatchedResource(
 		return err
 	}
 
-	result, err := restClient.ResourceInterface.Get(
-		ctx,
-		model.Name.ValueString(),
-		meta_v1.GetOptions{},
-	)
+	result, err := getObject(ctx, restClient.ResourceInterface, patchTargetGVK(model),
+		model.Name.ValueString(), subresourceName(model.Subresource))
 	if err != nil {
 		return err
 	}
@@ -636,11 +640,9 @@ func (r *patchResource) revertPatch(
 	}
 
 	// Fetch the live resource so we can compute the correct post-removal arrays.
-	current, err := restClient.ResourceInterface.Get(
-		ctx,
-		model.Name.ValueString(),
-		meta_v1.GetOptions{},
-	)
+	subresource := subresourceName(model.Subresource)
+	current, err := getObject(ctx, restClient.ResourceInterface, patchTargetGVK(model),
+		model.Name.ValueString(), subresource)
 	if err != nil {
 		return fmt.Errorf("failed to get current resource for revert: %w", err)
 	}
@@ -664,7 +666,7 @@ func (r *patchResource) revertPatch(
 		meta["namespace"] = model.Namespace.ValueString()
 	}
 
-	jsonData, err := json.Marshal(payload)
+	jsonData, err := json.Marshal(subresourceBody(payload, subresource))
 	if err != nil {
 		return fmt.Errorf("failed to marshal revert payload: %w", err)
 	}
@@ -681,6 +683,7 @@ func (r *patchResource) revertPatch(
 		meta_v1.PatchOptions{
 			FieldManager: fieldManagerName,
 		},
+		subresourceArgs(subresource)...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to revert patch: %w", err)
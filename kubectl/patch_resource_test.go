@@ -126,6 +126,34 @@ func TestIntegration_Patch_PatchSpec(t *testing.T) {
 	deleteDeployment(t, deployName, "default")
 }
 
+// TestIntegration_Patch_ScaleSubresource sets the replicas of a Deployment
+// through its scale subresource.
+func TestIntegration_Patch_ScaleSubresource(t *testing.T) {
+	t.Parallel()
+
+	deployName := testAccRandomName("patch-scale")
+
+	createDeployment(t, deployName, "default", 1)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { preCheck(t) },
+		ProtoV6ProviderFactories: integrationProviderCfg,
+		Steps: []resource.TestStep{
+			{
+				Config: patchScaleSubresourceConfig(deployName, 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"kubectl_patch.scale", "object.kind", "Deployment"),
+					resource.TestCheckResourceAttr(
+						"kubectl_patch.scale", "object.spec.replicas", "2"),
+				),
+			},
+		},
+	})
+
+	deleteDeployment(t, deployName, "default")
+}
+
 // TestIntegration_Patch_FieldManagerForceConflicts tests force_conflicts option.
 func TestIntegration_Patch_FieldManagerForceConflicts(t *testing.T) {
 	t.Parallel()
@@ -352,6 +380,24 @@ func patchFieldManagerConfig(name string) string {
 
 // --- Direct K8s helpers for test setup ---
 
+func patchScaleSubresourceConfig(name string, replicas int) string {
+	return fmt.Sprintf(`
+resource "kubectl_patch" "scale" {
+  api_version = "apps/v1"
+  kind        = "Deployment"
+  name        = %[1]q
+  namespace   = "default"
+  subresource = "scale"
+
+  patch = {
+    spec = {
+      replicas = %[2]d
+    }
+  }
+}
+`, name, replicas)
+}
+
 func createDeployment(t *testing.T, name, namespace string, replicas int) {
 	t.Helper()
 	deploy := &meta_v1_unstruct.Unstructured{
@@ -6,6 +6,8 @@
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
 	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/util"
@@ -13,17 +15,15 @@
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sresource "k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,17 +48,33 @@ type kubectlProviderData struct {
 	ApplyRetryCount  int64
 	terraformVersion string
 
+	// cacheDir is the root of the persistent discovery, OpenAPI and CRD
+	// cache. Caching to disk is disabled when empty.
+	cacheDir string
+	cacheTTL time.Duration
+
+	// schemaSources lists local OpenAPI documents and CRD manifests that
+	// take precedence over the schemas served by the cluster.
+	schemaSources []string
+
+	// planDryRun is the default of plan_dry_run for kubectl_manifest.
+	planDryRun bool
+
 	// Lazily initialized clients
-	logger          hclog.Logger
-	clientConfig    cache[clientcmd.ClientConfig]
-	restConfig      cache[*restclient.Config]
-	mainClientset   cache[*kubernetes.Clientset]
-	dynamicClient   cache[dynamic.Interface]
-	discoveryClient cache[discovery.DiscoveryInterface]
-	restMapper      cache[meta.RESTMapper]
-	restClient      cache[restclient.Interface]
-	OAPIFoundry     cache[api.Foundry]
-	crds            cache[[]unstructured.Unstructured]
+	logger                hclog.Logger
+	clientConfig          cache[clientcmd.ClientConfig]
+	restConfig            cache[*restclient.Config]
+	dynamicClient         cache[dynamic.Interface]
+	discoveryClient       cache[discovery.DiscoveryInterface]
+	cachedDiscoveryClient cache[discovery.CachedDiscoveryInterface]
+	restMapper            cache[meta.RESTMapper]
+	restClient            cache[restclient.Interface]
+	diskCache             cache[*diskCache]
+	localSchemas          cache[*localSchemas]
+	OAPIFoundry           cache[api.Foundry]
+	OAPIv3Foundries       sync.Map // schema.GroupVersion -> *cache[api.Foundry]
+	crdSchemas            sync.Map // schema.GroupVersionKind -> *cache[any]
+	crdFoundries          sync.Map // crdFoundryKey -> *cache[api.Foundry]
 }
 
 // getClientConfig lazily initializes and returns the clientcmd.ClientConfig.
@@ -86,17 +102,6 @@ func (p *kubectlProviderData) getRestConfig() (*restclient.Config, error) {
 	})
 }
 
-// getMainClientset lazily initializes and returns the Kubernetes clientset.
-func (p *kubectlProviderData) getMainClientset() (*kubernetes.Clientset, error) {
-	return p.mainClientset.Get(func() (*kubernetes.Clientset, error) {
-		cfg, err := p.getRestConfig()
-		if err != nil {
-			return nil, err
-		}
-		return kubernetes.NewForConfig(cfg)
-	})
-}
-
 // Implement k8sresource.RESTClientGetter interface for kubectlProviderData.
 var _ k8sresource.RESTClientGetter = &kubectlProviderData{}
 
@@ -110,14 +115,7 @@ func (p *kubectlProviderData) ToRESTConfig() (*restclient.Config, error) {
 }
 
 func (p *kubectlProviderData) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
-	config, err := p.ToRESTConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	return memory.NewMemCacheClient(
-		discovery.NewDiscoveryClientForConfigOrDie(config),
-	), nil
+	return p.getCachedDiscoveryClient()
 }
 
 func (p *kubectlProviderData) ToRESTMapper() (meta.RESTMapper, error) {
@@ -125,8 +123,11 @@ func (p *kubectlProviderData) ToRESTMapper() (meta.RESTMapper, error) {
 	if err != nil {
 		return nil, err
 	}
+	mapper, err := p.getRestMapper()
+	if err != nil {
+		return nil, err
+	}
 
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
 	expander := restmapper.NewShortcutExpander(mapper, discoveryClient, func(msg string) {
 		// Log warnings silently
 	})
@@ -251,6 +252,32 @@ func (p *kubectlProvider) Schema(
 				Description: "Server name passed to the server for SNI and is used in the client " +
 					"to check server certificates against. Can be set with KUBE_TLS_SERVER_NAME environment variable.",
 			},
+			"cache_dir": schema.StringAttribute{
+				Optional: true,
+				Description: "Directory used to persist API discovery, the OpenAPI document and CRD schemas " +
+					"between runs, keyed by cluster host and server version. Caching to disk is disabled " +
+					"when unset. Can be set with KUBECTL_PROVIDER_CACHE_DIR environment variable.",
+			},
+			"schema_sources": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Local OpenAPI v2/v3 documents and CRD manifests used to type resources before " +
+					"asking the API server, allowing plans without cluster access. Each entry may be a file, " +
+					"a directory or a glob pattern. Can be set with KUBECTL_PROVIDER_SCHEMA_SOURCES " +
+					"environment variable.",
+			},
+			"cache_ttl": schema.StringAttribute{
+				Optional: true,
+				Description: "How long cached data in `cache_dir` is used before it is revalidated " +
+					"against the API server, as a Go duration string. Defaults to `10m`. " +
+					"Can be set with KUBECTL_PROVIDER_CACHE_TTL environment variable.",
+			},
+			"plan_dry_run": schema.BoolAttribute{
+				Optional: true,
+				Description: "Default for `plan_dry_run` on `kubectl_manifest`: send a server-side dry-run apply " +
+					"during plan so admission errors surface early and `object` shows the defaulted result. " +
+					"Defaults to false. Can be set with KUBECTL_PROVIDER_PLAN_DRY_RUN environment variable.",
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"exec": schema.ListNestedBlock{
@@ -320,6 +347,10 @@ func (p *kubectlProvider) Configure(
 	kubeProxy := os.Getenv("KUBE_PROXY_URL")
 	applyRetryCountStr := os.Getenv("KUBECTL_PROVIDER_APPLY_RETRY_COUNT")
 	loadConfigFileStr := os.Getenv("KUBE_LOAD_CONFIG_FILE")
+	cacheDir := os.Getenv("KUBECTL_PROVIDER_CACHE_DIR")
+	cacheTTLStr := os.Getenv("KUBECTL_PROVIDER_CACHE_TTL")
+	schemaSourcesStr := os.Getenv("KUBECTL_PROVIDER_SCHEMA_SOURCES")
+	planDryRunStr := os.Getenv("KUBECTL_PROVIDER_PLAN_DRY_RUN")
 
 	var config util.ConfigData
 	diags := req.Config.Get(ctx, &config)
@@ -376,6 +407,15 @@ func (p *kubectlProvider) Configure(
 	if !config.LoadConfigFile.IsNull() {
 		loadConfigFileStr = strconv.FormatBool(config.LoadConfigFile.ValueBool())
 	}
+	if !config.CacheDir.IsNull() {
+		cacheDir = config.CacheDir.ValueString()
+	}
+	if !config.CacheTTL.IsNull() {
+		cacheTTLStr = config.CacheTTL.ValueString()
+	}
+	if !config.PlanDryRun.IsNull() {
+		planDryRunStr = strconv.FormatBool(config.PlanDryRun.ValueBool())
+	}
 
 	// Resolve apply_retry_count
 	applyRetryCount := int64(1)
@@ -388,6 +428,29 @@ func (p *kubectlProvider) Configure(
 		applyRetryCount = config.ApplyRetryCount.ValueInt64()
 	}
 
+	// Resolve cache_ttl
+	cacheTTL := defaultCacheTTL
+	if cacheTTLStr != "" {
+		parsed, err := time.ParseDuration(cacheTTLStr)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cache_ttl"),
+				"Invalid cache_ttl",
+				fmt.Sprintf("cache_ttl must be a valid duration such as \"10m\": %s", err),
+			)
+			return
+		}
+		if parsed < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("cache_ttl"),
+				"Invalid cache_ttl",
+				fmt.Sprintf("cache_ttl must not be negative, got %q", cacheTTLStr),
+			)
+			return
+		}
+		cacheTTL = parsed
+	}
+
 	// Resolve insecure and load_config_file booleans
 	var kubeInsecure bool
 	if kubeInsecureStr != "" {
@@ -401,6 +464,12 @@ func (p *kubectlProvider) Configure(
 			loadConfigFile = parsed
 		}
 	}
+	var planDryRun bool
+	if planDryRunStr != "" {
+		if parsed, err := strconv.ParseBool(planDryRunStr); err == nil {
+			planDryRun = parsed
+		}
+	}
 
 	// Resolve config_paths list
 	var kubeConfigPathsList []string
@@ -414,6 +483,18 @@ func (p *kubectlProvider) Configure(
 		kubeConfigPathsList = append(kubeConfigPathsList, paths...)
 	}
 
+	// Resolve schema_sources list
+	var schemaSources []string
+	if schemaSourcesStr != "" {
+		schemaSources = filepath.SplitList(schemaSourcesStr)
+	}
+	if !config.SchemaSources.IsNull() {
+		var sources []string
+		diags = config.SchemaSources.ElementsAs(ctx, &sources, false)
+		resp.Diagnostics.Append(diags...)
+		schemaSources = sources
+	}
+
 	// Build the resolved ConfigData with plain Go values (like Helm's
 	// kubernetesConfigObjectValue). Unknown values have already been
 	// resolved to "" / false through the env-var overlay above.
@@ -434,6 +515,10 @@ func (p *kubectlProvider) Configure(
 		Token:                 types.StringValue(kubeToken),
 		ProxyURL:              types.StringValue(kubeProxy),
 		LoadConfigFile:        types.BoolValue(loadConfigFile),
+		CacheDir:              types.StringValue(cacheDir),
+		CacheTTL:              types.StringValue(cacheTTL.String()),
+		SchemaSources:         util.StringListToFramework(ctx, schemaSources),
+		PlanDryRun:            types.BoolValue(planDryRun),
 		Exec:                  config.Exec,
 	}
 
@@ -443,6 +528,10 @@ func (p *kubectlProvider) Configure(
 		configFullyKnown: req.Config.Raw.IsFullyKnown(),
 		ApplyRetryCount:  applyRetryCount,
 		terraformVersion: req.TerraformVersion,
+		cacheDir:         cacheDir,
+		cacheTTL:         cacheTTL,
+		schemaSources:    schemaSources,
+		planDryRun:       planDryRun,
 		logger:           hclog.Default(),
 	}
 
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/api"
+	"github.com/hashicorp-oss/terraform-provider-kubectl/kubectl/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// localSchemas holds the type information loaded from the provider's
+// schema_sources, consulted before the API server so that plans can be
+// produced without contacting the cluster.
+type localSchemas struct {
+	crds      []*unstructured.Unstructured
+	foundries []api.Foundry
+}
+
+// getLocalSchemas lazily loads the files listed in schema_sources.
+func (p *kubectlProviderData) getLocalSchemas() (*localSchemas, error) {
+	return p.localSchemas.Get(func() (*localSchemas, error) {
+		return loadSchemaSources(p.schemaSources)
+	})
+}
+
+// loadSchemaSources reads OpenAPI v2 and v3 documents and CRD manifests from
+// the given paths. Each path may name a file, a directory (whose .json, .yaml
+// and .yml files are read) or a glob pattern.
+func loadSchemaSources(sources []string) (*localSchemas, error) {
+	ls := &localSchemas{}
+	for _, src := range sources {
+		files, err := expandSchemaSource(src)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if err := ls.loadFile(f); err != nil {
+				return nil, fmt.Errorf("failed to load schema source %s: %w", f, err)
+			}
+		}
+	}
+	return ls, nil
+}
+
+func expandSchemaSource(src string) ([]string, error) {
+	fi, err := os.Stat(src)
+	if err == nil && fi.IsDir() {
+		var files []string
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".json", ".yaml", ".yml":
+				if !e.IsDir() {
+					files = append(files, filepath.Join(src, e.Name()))
+				}
+			}
+		}
+		return files, nil
+	}
+	if err == nil {
+		return []string{src}, nil
+	}
+
+	files, gerr := filepath.Glob(src)
+	if gerr != nil {
+		return nil, fmt.Errorf("invalid schema source %q: %w", src, gerr)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("schema source %q does not match any files", src)
+	}
+	return files, nil
+}
+
+func (ls *localSchemas) loadFile(name string) error {
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	// JSON documents (typically OpenAPI specs dumped from a cluster) are used
+	// as-is; anything else is treated as a stream of YAML documents.
+	docs := []string{string(raw)}
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		docs, err = yaml.SplitMultiDocumentYAML(string(raw))
+		if err != nil {
+			return err
+		}
+	}
+	for _, doc := range docs {
+		js, err := k8syaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return err
+		}
+		var head map[string]any
+		if err := json.Unmarshal(js, &head); err != nil || head == nil {
+			continue
+		}
+
+		switch {
+		case head["swagger"] != nil:
+			f, err := api.NewFoundryFromSpecV2(js)
+			if err != nil {
+				return err
+			}
+			ls.foundries = append(ls.foundries, f)
+		case head["openapi"] != nil:
+			f, err := api.NewFoundryFromSpecV3(js)
+			if err != nil {
+				return err
+			}
+			ls.foundries = append(ls.foundries, f)
+		default:
+			u := &unstructured.Unstructured{Object: head}
+			switch {
+			case u.GroupVersionKind().GroupKind() == crdGroupKind:
+				ls.crds = append(ls.crds, u)
+			case u.IsList():
+				_ = u.EachListItem(func(o runtime.Object) error {
+					if item, ok := o.(*unstructured.Unstructured); ok &&
+						item.GroupVersionKind().GroupKind() == crdGroupKind {
+						ls.crds = append(ls.crds, item)
+					}
+					return nil
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// crdSchema returns the openAPIV3Schema for gvk from a local CRD, or nil.
+func (ls *localSchemas) crdSchema(gvk schema.GroupVersionKind) any {
+	if ls == nil {
+		return nil
+	}
+	for _, crd := range ls.crds {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if group != gvk.Group || kind != gvk.Kind {
+			continue
+		}
+		if s := crdVersionSchema(crd, gvk.Version); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// foundryByGVK returns the first local OpenAPI document that defines gvk, or nil.
+func (ls *localSchemas) foundryByGVK(gvk schema.GroupVersionKind) api.Foundry {
+	if ls == nil {
+		return nil
+	}
+	for _, f := range ls.foundries {
+		if _, _, err := f.GetSchemaByGVK(gvk); err == nil {
+			return f
+		}
+	}
+	return nil
+}
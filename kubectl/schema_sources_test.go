@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testSchemaSourceCRD = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                size:
+                  type: integer
+`
+
+const testSchemaSourceOpenAPI = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Kubernetes", "version": "v1.31.0"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "io.k8s.api.core.v1.ConfigMap": {
+        "type": "object",
+        "properties": {
+          "immutable": {"type": "boolean"}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "ConfigMap"}]
+      }
+    }
+  }
+}`
+
+func TestLoadSchemaSources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"crds.yaml": testSchemaSourceCRD,
+		"core.json": testSchemaSourceOpenAPI,
+		"notes.txt": "not a schema",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ls, err := loadSchemaSources([]string{dir})
+	if err != nil {
+		t.Fatalf("load: %s", err)
+	}
+
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	if ls.crdSchema(widget) == nil {
+		t.Fatal("expected local CRD schema for Widget")
+	}
+	widgetV2 := schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Widget"}
+	if ls.crdSchema(widgetV2) != nil {
+		t.Fatal("expected no schema for unknown CRD version")
+	}
+
+	cm := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	f := ls.foundryByGVK(cm)
+	if f == nil {
+		t.Fatal("expected ConfigMap from local OpenAPI document")
+	}
+	typ, _, err := f.GetTypeByGVK(cm)
+	if err != nil {
+		t.Fatalf("type: %s", err)
+	}
+	expected := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"immutable": tftypes.Bool}}
+	if !typ.Equal(expected) {
+		t.Fatalf("unexpected type: %s", typ)
+	}
+
+	if _, err := loadSchemaSources([]string{filepath.Join(dir, "missing-*.yaml")}); err == nil {
+		t.Fatal("expected error for a pattern that matches nothing")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Subresources that kubectl_manifest and kubectl_patch can write to.
+const (
+	subresourceStatus = "status"
+	subresourceScale  = "scale"
+)
+
+// scaleGroupVersionKind is the kind served by scale subresources.
+var scaleGroupVersionKind = k8sschema.GroupVersionKind{
+	Group:   "autoscaling",
+	Version: "v1",
+	Kind:    "Scale",
+}
+
+// subresourceName returns the value of a subresource attribute, or "" for
+// the main resource.
+func subresourceName(v types.String) string {
+	if v.IsNull() || v.IsUnknown() {
+		return ""
+	}
+	return v.ValueString()
+}
+
+// subresourceArgs returns the trailing subresource arguments of the dynamic
+// client methods for subresource.
+func subresourceArgs(subresource string) []string {
+	if subresource == "" {
+		return nil
+	}
+	return []string{subresource}
+}
+
+// subresourceBody returns obj, an object of its own kind, as the body of a
+// request to subresource. The scale subresource takes an autoscaling/v1
+// Scale, which carries the name, namespace and spec.replicas of obj.
+func subresourceBody(obj map[string]any, subresource string) map[string]any {
+	if subresource != subresourceScale {
+		return obj
+	}
+	uo := meta_v1_unstruct.Unstructured{Object: obj}
+	scale := &meta_v1_unstruct.Unstructured{Object: map[string]any{}}
+	scale.SetGroupVersionKind(scaleGroupVersionKind)
+	scale.SetName(uo.GetName())
+	scale.SetNamespace(uo.GetNamespace())
+	if replicas, ok, _ := meta_v1_unstruct.NestedFieldNoCopy(obj, "spec", "replicas"); ok {
+		scale.Object["spec"] = map[string]any{"replicas": replicas}
+	}
+	return scale.Object
+}
+
+// subresourceObject returns result, the response to a request to
+// subresource of an object of kind gvk, in the shape of that kind. A Scale
+// becomes an object with its metadata, spec.replicas and status.replicas.
+func subresourceObject(
+	result *meta_v1_unstruct.Unstructured,
+	gvk k8sschema.GroupVersionKind,
+	subresource string,
+) *meta_v1_unstruct.Unstructured {
+	if subresource != subresourceScale || result.GroupVersionKind() != scaleGroupVersionKind {
+		return result
+	}
+	obj := &meta_v1_unstruct.Unstructured{Object: map[string]any{}}
+	obj.SetGroupVersionKind(gvk)
+	if metadata, ok := result.Object["metadata"]; ok {
+		obj.Object["metadata"] = metadata
+	}
+	for _, field := range []string{"spec", "status"} {
+		replicas, ok, _ := meta_v1_unstruct.NestedFieldNoCopy(result.Object, field, "replicas")
+		if ok {
+			obj.Object[field] = map[string]any{"replicas": replicas}
+		}
+	}
+	return obj
+}
+
+// getObject reads the named object of kind gvk from rs, through subresource
+// if it is set.
+func getObject(
+	ctx context.Context,
+	rs dynamic.ResourceInterface,
+	gvk k8sschema.GroupVersionKind,
+	name, subresource string,
+) (*meta_v1_unstruct.Unstructured, error) {
+	result, err := rs.Get(ctx, name, meta_v1.GetOptions{}, subresourceArgs(subresource)...)
+	if err != nil {
+		return nil, err
+	}
+	return subresourceObject(result, gvk, subresource), nil
+}
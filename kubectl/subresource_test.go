@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubectl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	meta_v1_unstruct "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var subresourceTestDeployment = map[string]any{
+	"apiVersion": "apps/v1",
+	"kind":       "Deployment",
+	"metadata":   map[string]any{"name": "web", "namespace": "default"},
+	"spec": map[string]any{
+		"replicas": int64(3),
+		"selector": map[string]any{"matchLabels": map[string]any{"app": "web"}},
+	},
+	"status": map[string]any{"readyReplicas": int64(1)},
+}
+
+func TestSubresourceBody(t *testing.T) {
+	samples := map[string]struct {
+		subresource string
+		obj         map[string]any
+		expected    map[string]any
+	}{
+		"main resource": {
+			obj:      subresourceTestDeployment,
+			expected: subresourceTestDeployment,
+		},
+		"status": {
+			subresource: subresourceStatus,
+			obj:         subresourceTestDeployment,
+			expected:    subresourceTestDeployment,
+		},
+		"scale": {
+			subresource: subresourceScale,
+			obj:         subresourceTestDeployment,
+			expected: map[string]any{
+				"apiVersion": "autoscaling/v1",
+				"kind":       "Scale",
+				"metadata":   map[string]any{"name": "web", "namespace": "default"},
+				"spec":       map[string]any{"replicas": int64(3)},
+			},
+		},
+		"scale without replicas": {
+			subresource: subresourceScale,
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"metadata":   map[string]any{"name": "db"},
+			},
+			expected: map[string]any{
+				"apiVersion": "autoscaling/v1",
+				"kind":       "Scale",
+				"metadata":   map[string]any{"name": "db"},
+			},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			if got := subresourceBody(s.obj, s.subresource); !reflect.DeepEqual(got, s.expected) {
+				t.Fatalf("expected %v, got %v", s.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetObject(t *testing.T) {
+	ctx := context.Background()
+	gvk := k8sschema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	gvr := k8sschema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	scale := map[string]any{
+		"apiVersion": "autoscaling/v1",
+		"kind":       "Scale",
+		"metadata":   map[string]any{"name": "web", "namespace": "default", "uid": "1234"},
+		"spec":       map[string]any{"replicas": int64(3)},
+		"status":     map[string]any{"replicas": int64(2), "selector": "app=web"},
+	}
+
+	samples := map[string]struct {
+		subresource string
+		expected    map[string]any
+	}{
+		"main resource": {expected: subresourceTestDeployment},
+		"status":        {subresource: subresourceStatus, expected: subresourceTestDeployment},
+		"scale": {
+			subresource: subresourceScale,
+			expected: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "web", "namespace": "default", "uid": "1234"},
+				"spec":       map[string]any{"replicas": int64(3)},
+				"status":     map[string]any{"replicas": int64(2)},
+			},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			var requested string
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			client.PrependReactor("get", "deployments",
+				func(a k8stesting.Action) (bool, runtime.Object, error) {
+					requested = a.GetSubresource()
+					obj := subresourceTestDeployment
+					if requested == subresourceScale {
+						obj = scale
+					}
+					return true, &meta_v1_unstruct.Unstructured{Object: obj}, nil
+				})
+			rs := client.Resource(gvr).Namespace("default")
+
+			got, err := getObject(ctx, rs, gvk, "web", s.subresource)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requested != s.subresource {
+				t.Fatalf("expected a request to %q, got %q", s.subresource, requested)
+			}
+			if !reflect.DeepEqual(got.Object, s.expected) {
+				t.Fatalf("expected %v, got %v", s.expected, got.Object)
+			}
+		})
+	}
+}
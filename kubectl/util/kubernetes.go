@@ -37,6 +37,10 @@ type ConfigData struct {
 	ProxyURL              types.String `tfsdk:"proxy_url"`
 	LoadConfigFile        types.Bool   `tfsdk:"load_config_file"`
 	TLSServerName         types.String `tfsdk:"tls_server_name"`
+	CacheDir              types.String `tfsdk:"cache_dir"`
+	CacheTTL              types.String `tfsdk:"cache_ttl"`
+	SchemaSources         types.List   `tfsdk:"schema_sources"`
+	PlanDryRun            types.Bool   `tfsdk:"plan_dry_run"`
 	Exec                  types.List   `tfsdk:"exec"`
 }
 